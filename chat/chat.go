@@ -1,26 +1,127 @@
+// Package chat embeds the chat into another Go application:
+//
+//	app, err := chat.New(chat.WithDB(pool), chat.WithConfig(cfg))
+//	defer app.Close()
+//	app.CreateTables()
+//	r.Route(app.BasePath(), app.RegisterRoutes)
 package chat
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
+	"sync"
 
 	"github.com/acakp/dumbchat/config"
+	"github.com/acakp/dumbchat/internal/adapter/blobstore"
 	"github.com/acakp/dumbchat/internal/adapter/postgres"
 	"github.com/acakp/dumbchat/internal/adapter/templates"
 	httpctrl "github.com/acakp/dumbchat/internal/controller/http"
 	v1 "github.com/acakp/dumbchat/internal/controller/http/v1"
 	"github.com/acakp/dumbchat/internal/controller/ws"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/command"
 	"github.com/go-chi/chi/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type App struct {
 	handler *v1.Handler
-	// other unexported fields
+	// the hub created by New, nil for one given with WithHub
+	ownHub *ws.Hub
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	once   sync.Once
 }
 
+// New creates the chat and starts its background work: the websocket
+// hub, webhook deliveries and removing the files of messages deleted
+// while the chat was down. Call Close to stop them
+func New(opts ...Option) (*App, error) {
+	o := options{cfg: config.Default()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.db == nil {
+		return nil, errors.New("chat.New: a database is required, see WithDB")
+	}
+	if o.basePath != nil {
+		o.cfg.BasePath = *o.basePath
+	}
+	if err := o.cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("chat.New: %w", err)
+	}
+
+	if o.blobs == nil {
+		blobs, err := blobstore.NewLocal(o.cfg.UploadDir)
+		if err != nil {
+			return nil, fmt.Errorf("blobstore.NewLocal: %w", err)
+		}
+		o.blobs = blobs
+	}
+
+	var tmpls templates.ParsedTemplates
+	if o.layout != nil {
+		layout, err := o.layout.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("chat.New: %w", err)
+		}
+		tmpls = templates.ParseTemplates(layout)
+	} else {
+		tmpls = templates.ParseTemplatesCmd()
+	}
+	if tmpls.Err != nil {
+		return nil, tmpls.Err
+	}
+
+	a := &App{}
+	hub := o.hub
+	if hub == nil {
+		hub = ws.New()
+		a.ownHub = hub
+	}
+	a.handler = v1.New(o.cfg, o.db, hub, &tmpls, o.blobs)
+	a.handler.Hooks = o.hooks.handlerHooks()
+	if o.logger != nil {
+		a.handler.Log = *o.logger
+	}
+	if o.hooks.OnConnect != nil {
+		hub.OnConnect = o.hooks.OnConnect
+	}
+	if o.hooks.OnDisconnect != nil {
+		hub.OnDisconnect = o.hooks.OnDisconnect
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	if a.ownHub != nil {
+		a.wg.Go(a.ownHub.Run)
+	}
+	a.wg.Go(func() { a.handler.RunWebhooks(ctx) })
+	a.wg.Go(func() { usecase.CleanupAttachments(a.handler.Log.WithContext(ctx), o.db, o.blobs) })
+	return a, nil
+}
+
+// Close stops the background work and disconnects the websocket
+// clients. The routes shouldn't be served after it
+func (a *App) Close() {
+	a.once.Do(func() {
+		a.cancel()
+		if a.ownHub != nil {
+			a.ownHub.Close()
+		}
+		a.wg.Wait()
+	})
+}
+
+// AttachTemplates renders the chat page into the given layout, see WithTemplates
 func (a *App) AttachTemplates(t *template.Template) error {
+	t, err := t.Clone()
+	if err != nil {
+		return err
+	}
 	tmpls := templates.ParseTemplates(t)
 	if tmpls.Err != nil {
 		return tmpls.Err
@@ -30,26 +131,50 @@ func (a *App) AttachTemplates(t *template.Template) error {
 	return nil
 }
 
+// Execute writes the chat page as a visitor without cookies sees it.
+// Errors are logged, the chat is written empty if messages can't be loaded
 func (a *App) Execute(wr io.Writer) {
-	a.handler.Tmpls.ChatTmpl.Execute(wr, a.handler.URLs)
-}
-
-func New(dbpool *pgxpool.Pool) (*App, error) {
-	cfg, err := config.Init()
+	h := a.handler
+	view, err := usecase.GetChatView(h.DBPool, domain.Viewer{}, h.URLs, h.Cfg)
 	if err != nil {
-		return &App{}, fmt.Errorf("Error initializing config for new app (chat.go): %v\n", err)
+		h.Log.Error().Err(err).Msg("Failed to load chat")
+		view = domain.ChatView{
+			Pinned:            domain.PinnedView{URLs: h.URLs},
+			URLs:              h.URLs,
+			MaxMessageLength:  h.Cfg.MaxMessageLength,
+			MaxNicknameLength: h.Cfg.MaxNicknameLength,
+		}
 	}
+	view.ReadOnly = h.Hub.ReadOnly()
+	if err = h.Tmpls.ChatTmpl.Execute(wr, view); err != nil {
+		h.Log.Error().Err(err).Msg("Failed to render chat template")
+	}
+}
 
-	hub := ws.New()
-	h := v1.New(cfg, dbpool, hub, nil)
-
-	return &App{handler: h}, nil
+// BasePath is the path the routes expect to be mounted at
+func (a *App) BasePath() string {
+	if a.handler.URLs.Base == "" {
+		return "/"
+	}
+	return a.handler.URLs.Base
 }
 
+// RegisterRoutes adds the chat routes, r must be mounted at BasePath
 func (a *App) RegisterRoutes(r chi.Router) {
 	httpctrl.RegisterRoutes(r, a.handler)
 }
 
-func (a *App) CreateTables(dbpool *pgxpool.Pool) {
-	postgres.CreateTables(dbpool)
+// RegisterCommand adds a slash command, a built-in one
+// with the same name is replaced
+func (a *App) RegisterCommand(c command.Command) error {
+	return a.handler.Commands.Register(c)
+}
+
+// CreateTables creates or migrates the chat tables and restores the lockdown
+// mode saved before a restart, and the site secret if Config.SiteSecret is empty
+func (a *App) CreateTables() error {
+	if err := postgres.CreateTables(a.handler.DBPool); err != nil {
+		return err
+	}
+	return a.handler.RestoreState()
 }
@@ -0,0 +1,302 @@
+package chat_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/acakp/dumbchat/chat"
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// memBlobs keeps blobs in memory
+type memBlobs struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func (m *memBlobs) Put(key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blobs[key] = data
+	return nil
+}
+
+func (m *memBlobs) Open(key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.blobs[key]
+	if !ok {
+		return nil, errors.New("no such blob")
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memBlobs) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.blobs, key)
+	return nil
+}
+
+// a pool that never connects, requests that need the database fail
+func unreachableDB(t *testing.T) *pgxpool.Pool {
+	pool, err := pgxpool.New(context.Background(), "host=127.0.0.1 port=1 connect_timeout=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// mounts the app at its base path like a host application does
+func serve(t *testing.T, app *chat.App) *httptest.Server {
+	r := chi.NewRouter()
+	r.Route(app.BasePath(), app.RegisterRoutes)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func socketURL(srv *httptest.Server, app *chat.App) string {
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + app.BasePath() + "/ws"
+}
+
+// the logger is written to by requests and background workers at once
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestNewRequiresDB(t *testing.T) {
+	if _, err := chat.New(); err == nil {
+		t.Error("New without a database succeeded")
+	}
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	cfg := chat.DefaultConfig()
+	cfg.PermalinkContext = -1
+	if _, err := chat.New(chat.WithDB(unreachableDB(t)), chat.WithConfig(cfg)); err == nil {
+		t.Error("New with a negative PermalinkContext succeeded")
+	}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	blobs := &memBlobs{blobs: map[string][]byte{"preview-abc.png": []byte("png")}}
+	var logs syncBuffer
+	app, err := chat.New(
+		chat.WithDB(unreachableDB(t)),
+		chat.WithBasePath("/talk"),
+		chat.WithBlobStore(blobs),
+		chat.WithLogger(zerolog.New(&logs)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Close()
+	if app.BasePath() != "/talk" {
+		t.Errorf("BasePath() = %q, want /talk", app.BasePath())
+	}
+	srv := serve(t, app)
+
+	// served from the given blob store
+	resp, err := http.Get(srv.URL + "/talk/previews/preview-abc.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "png" || resp.Header.Get("Content-Type") != "image/png" {
+		t.Errorf("preview image: %d %q %q", resp.StatusCode, resp.Header.Get("Content-Type"), body)
+	}
+
+	// errors are logged through the given logger
+	resp, err = http.Get(srv.URL + "/talk/attachments/upload-abc.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("attachment without a database: got %d, want 500", resp.StatusCode)
+	}
+	// the request is logged after the response is sent
+	waitForLog(t, &logs, `"path":"/talk/attachments/upload-abc.png"`)
+	// so is the attachment cleanup New starts
+	waitForLog(t, &logs, "Failed to clean up attachments")
+}
+
+func waitForLog(t *testing.T, logs *syncBuffer, s string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(logs.String(), s) {
+		if time.Now().After(deadline) {
+			t.Fatalf("%q wasn't logged through WithLogger, got %q", s, logs.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWithTemplatesKeepsLayout(t *testing.T) {
+	layout := template.Must(template.New("layout").Parse(`<main>{{ template "chat" . }}</main>`))
+	app, err := chat.New(
+		chat.WithDB(unreachableDB(t)),
+		chat.WithBlobStore(&memBlobs{blobs: map[string][]byte{}}),
+		chat.WithTemplates(layout),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Close()
+	if err = app.AttachTemplates(layout); err != nil {
+		t.Fatal(err)
+	}
+	if layout.Lookup("chat") != nil {
+		t.Error("the chat templates were added to the host's layout")
+	}
+
+	var page bytes.Buffer
+	app.Execute(&page)
+	if !strings.HasPrefix(page.String(), "<main>") || !strings.HasSuffix(page.String(), "</main>") ||
+		!strings.Contains(page.String(), `hx-post="/chat/messages"`) {
+		t.Errorf("chat page isn't rendered into the layout: %q", page.String())
+	}
+}
+
+func TestConnectHooks(t *testing.T) {
+	disconnected := make(chan chat.ClientInfo, 1)
+	app, err := chat.New(
+		chat.WithDB(unreachableDB(t)),
+		chat.WithBlobStore(&memBlobs{blobs: map[string][]byte{}}),
+		chat.WithHooks(chat.Hooks{
+			OnConnect: func(r *http.Request, c chat.ClientInfo) error {
+				if r.Header.Get("X-Test") == "refuse" {
+					return errors.New("sign in to chat")
+				}
+				return nil
+			},
+			OnDisconnect: func(c chat.ClientInfo) { disconnected <- c },
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer app.Close()
+	srv := serve(t, app)
+
+	// refused connections are closed with the error as the reason
+	conn, _, err := websocket.DefaultDialer.Dial(socketURL(srv, app), http.Header{"X-Test": {"refuse"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err = conn.ReadMessage()
+	conn.Close()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.ClosePolicyViolation || closeErr.Text != "sign in to chat" {
+		t.Errorf("refused connection: got %v", err)
+	}
+
+	conn, _, err = websocket.DefaultDialer.Dial(socketURL(srv, app), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var hello chat.Event
+	if err = conn.ReadJSON(&hello); err != nil || hello.Type != "hello" {
+		t.Fatalf("first event: %+v, %v", hello, err)
+	}
+	conn.Close()
+
+	select {
+	case c := <-disconnected:
+		if c.ID == "" {
+			t.Error("OnDisconnect got no client ID")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnDisconnect wasn't called")
+	}
+	// only accepted connections are reported
+	select {
+	case c := <-disconnected:
+		t.Errorf("unexpected OnDisconnect for %+v", c)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCloseDisconnectsClients(t *testing.T) {
+	app, err := chat.New(chat.WithDB(unreachableDB(t)), chat.WithBlobStore(&memBlobs{blobs: map[string][]byte{}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := serve(t, app)
+
+	conn, _, err := websocket.DefaultDialer.Dial(socketURL(srv, app), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err = conn.ReadMessage(); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Close()
+	if _, _, err = conn.ReadMessage(); err == nil {
+		t.Error("connection is still open after Close")
+	}
+	// safe to call twice
+	app.Close()
+}
+
+func TestWithHubIsLeftRunning(t *testing.T) {
+	hub := chat.NewHub()
+	go hub.Run()
+	defer hub.Close()
+
+	app, err := chat.New(chat.WithDB(unreachableDB(t)), chat.WithHub(hub), chat.WithBlobStore(&memBlobs{blobs: map[string][]byte{}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := serve(t, app)
+	app.Close()
+
+	// the host's hub still serves connections
+	conn, _, err := websocket.DefaultDialer.Dial(socketURL(srv, app), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var hello chat.Event
+	if err = conn.ReadJSON(&hello); err != nil || hello.Type != "hello" {
+		t.Errorf("first event: %+v, %v", hello, err)
+	}
+}
@@ -0,0 +1,81 @@
+package chat
+
+import (
+	"net/http"
+	"sync"
+
+	v1 "github.com/acakp/dumbchat/internal/controller/http/v1"
+	"github.com/acakp/dumbchat/internal/controller/ws"
+	"github.com/acakp/dumbchat/internal/domain"
+)
+
+// Message is a chat message as hooks and events see it
+type Message = domain.Message
+
+// Event is a chat event. Data holds the payload the clients receive:
+// a Message for new_message, edit_message, delete_message, hide_message
+// and unhide_message, the other payloads are documented in the README
+type Event = ws.Event
+
+// ClientInfo describes a websocket connection
+type ClientInfo = ws.ClientInfo
+
+// Hooks let the host application check and follow chat activity,
+// nil funcs are skipped. They run synchronously, so keep them fast
+type Hooks struct {
+	// runs before a message is saved, after its slash command and before
+	// the content checks, for bot messages too. It may change msg; an error
+	// rejects the message with 403 and its text is shown to the poster.
+	// Edits run it too, with msg.ID set; only the new content is saved
+	BeforeMessage func(r *http.Request, msg *Message) error
+	// runs after a message is saved and sent to the clients
+	AfterMessage func(msg Message)
+	// runs after messages are deleted by admins or their authors
+	OnDelete func(ids []int64)
+	// runs when a websocket connection opens, an error closes it
+	// and its text is sent as the close reason
+	OnConnect func(r *http.Request, c ClientInfo) error
+	// runs after a connection OnConnect accepted is closed
+	OnDisconnect func(c ClientInfo)
+}
+
+// WithHooks sets the hooks, see Hooks
+func WithHooks(hooks Hooks) Option {
+	return func(o *options) { o.hooks = hooks }
+}
+
+func (h Hooks) handlerHooks() v1.Hooks {
+	return v1.Hooks{
+		BeforeMessage: h.BeforeMessage,
+		AfterMessage:  h.AfterMessage,
+		OnDelete:      h.OnDelete,
+	}
+}
+
+// Subscribe calls fn with every event sent to all clients, and with
+// mention events. fn runs on the goroutine that caused the event, so it
+// must return quickly and must not post or subscribe; use Events otherwise.
+// Call the returned func to unsubscribe
+func (a *App) Subscribe(fn func(Event)) (unsubscribe func()) {
+	return a.handler.Subscribe(fn)
+}
+
+// Events returns a channel receiving the events Subscribe sees.
+// Events are dropped while the channel is full. Call the returned
+// func to unsubscribe, it closes the channel
+func (a *App) Events(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+	unsubscribe := a.handler.Subscribe(func(e Event) {
+		select {
+		case ch <- e:
+		default:
+		}
+	})
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			unsubscribe()
+			close(ch)
+		})
+	}
+}
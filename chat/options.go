@@ -0,0 +1,90 @@
+package chat
+
+import (
+	"html/template"
+
+	"github.com/acakp/dumbchat/config"
+	"github.com/acakp/dumbchat/internal/controller/ws"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// Config holds all settings, see DefaultConfig and ConfigFromEnv
+type Config = config.Config
+
+// BlobStore keeps uploaded files, the default one stores them in Config.UploadDir
+type BlobStore = domain.BlobStore
+
+// Hub fans websocket events out to the connected clients
+type Hub = ws.Hub
+
+// DefaultConfig returns the default settings. AdminHash has no default:
+// set it to a bcrypt hash of the admin password to enable the admin pages
+func DefaultConfig() Config {
+	return config.Default()
+}
+
+// ConfigFromEnv reads the settings from the environment variables
+// documented in the README. Flags and .env files aren't read
+func ConfigFromEnv() (Config, error) {
+	return config.FromEnv()
+}
+
+// NewHub returns a hub for WithHub
+func NewHub() *Hub {
+	return ws.New()
+}
+
+type Option func(*options)
+
+type options struct {
+	cfg      config.Config
+	db       *pgxpool.Pool
+	blobs    domain.BlobStore
+	hub      *ws.Hub
+	layout   *template.Template
+	logger   *zerolog.Logger
+	basePath *string
+	hooks    Hooks
+}
+
+// WithConfig replaces the default settings
+func WithConfig(cfg Config) Option {
+	return func(o *options) { o.cfg = cfg }
+}
+
+// WithDB sets the database, required
+func WithDB(pool *pgxpool.Pool) Option {
+	return func(o *options) { o.db = pool }
+}
+
+func WithBlobStore(blobs BlobStore) Option {
+	return func(o *options) { o.blobs = blobs }
+}
+
+// WithHub makes the App use a hub the caller runs with Hub.Run
+// and stops with Hub.Close, the App does neither
+func WithHub(hub *Hub) Option {
+	return func(o *options) { o.hub = hub }
+}
+
+// WithTemplates renders the chat page into the host's layout template.
+// Like web/templates/layout.html it must call the "chat" template and
+// set window.chatURLs. The chat templates are added to a clone, layout
+// itself is left as is. By default the page is standalone
+func WithTemplates(layout *template.Template) Option {
+	return func(o *options) { o.layout = layout }
+}
+
+// WithLogger sets the logger of the chat, zerolog's global logger
+// by default. The global logger itself is left as is
+func WithLogger(logger zerolog.Logger) Option {
+	return func(o *options) { o.logger = &logger }
+}
+
+// WithBasePath sets the path the routes are mounted at,
+// overriding Config.BasePath
+func WithBasePath(path string) Option {
+	return func(o *options) { o.basePath = &path }
+}
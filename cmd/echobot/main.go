@@ -0,0 +1,47 @@
+// echobot is an example bot: it answers "!echo <text>" messages with the text.
+//
+//	DUMBCHAT_URL=http://localhost:8080/chat DUMBCHAT_TOKEN=dct_... go run ./cmd/echobot
+//
+// The token needs the read and post scopes.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/acakp/dumbchat/pkg/client"
+)
+
+const nickname = "echobot"
+
+func main() {
+	c, err := client.New(os.Getenv("DUMBCHAT_URL"), os.Getenv("DUMBCHAT_TOKEN"), client.WithNickname(nickname))
+	if err != nil {
+		log.Fatal("error creating client: ", err)
+	}
+
+	c.OnMessage(func(ctx context.Context, m client.Message) {
+		text, ok := strings.CutPrefix(m.Content, "!echo ")
+		if !ok || m.Nickname == nickname {
+			return
+		}
+		if _, err := c.Reply(ctx, m, text); err != nil {
+			log.Print("error replying: ", err)
+		}
+	})
+	c.OnConnect(func(ctx context.Context) {
+		log.Print("connected")
+	})
+	c.OnDisconnect(func(err error) {
+		log.Print("disconnected: ", err)
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err = c.Run(ctx); err != nil && ctx.Err() == nil {
+		log.Fatal("client.Run: ", err)
+	}
+}
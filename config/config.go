@@ -3,6 +3,7 @@ package config
 import (
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/acakp/dumbchat/internal/adapter/postgres"
 	"github.com/acakp/dumbchat/pkg/logger"
@@ -11,14 +12,27 @@ import (
 )
 
 type Config struct {
-	Logger          logger.Config
-	DBConfig        postgres.Config
-	HttpPort        string   `env:"HTTP_PORT" envDefault:"8080"`
-	AdminHash       string   `env:"ADMIN_PASSWORD_HASH,required"`
-	BasePath        string   `env:"CHAT_BASE_PATH" envDefault:"/chat"`
-	BannedNicknames []string `env:"BANNED_NICKNAMES"`
+	Logger              logger.Config
+	DBConfig            postgres.Config
+	HttpPort            string        `env:"HTTP_PORT" envDefault:"8080"`
+	AdminHash           string        `env:"ADMIN_PASSWORD_HASH,required"`
+	BasePath            string        `env:"CHAT_BASE_PATH" envDefault:"/chat"`
+	BannedNicknames     []string      `env:"BANNED_NICKNAMES"`
+	ReportHideThreshold int           `env:"REPORT_HIDE_THRESHOLD" envDefault:"3"`
+	ReadOnly            bool          `env:"CHAT_READ_ONLY" envDefault:"false"`
+	SiteSecret          string        `env:"SITE_SECRET"`
+	EditWindow          time.Duration `env:"EDIT_WINDOW" envDefault:"5m"`
+	Markdown            bool          `env:"MARKDOWN_ENABLED" envDefault:"true"`
+	UploadDir           string        `env:"UPLOAD_DIR" envDefault:"uploads"`
+	MaxUploadBytes      int64         `env:"MAX_UPLOAD_BYTES" envDefault:"5242880"`
+	UploadMIMETypes     []string      `env:"UPLOAD_MIME_TYPES" envDefault:"image/jpeg,image/png,image/gif,application/pdf,text/plain"`
+	LinkPreviews        bool          `env:"LINK_PREVIEWS" envDefault:"true"`
+	PermalinkContext    int           `env:"PERMALINK_CONTEXT" envDefault:"20"`
+	MaxMessageLength    int           `env:"MAX_MESSAGE_LENGTH" envDefault:"4000"`
+	MaxNicknameLength   int           `env:"MAX_NICKNAME_LENGTH" envDefault:"32"`
 }
 
+// reads the env file given with -e, for the standalone server
 func Init() (Config, error) {
 	envPath := flag.String("e", ".env", "path to the env file")
 	flag.Parse()
@@ -27,12 +41,44 @@ func Init() (Config, error) {
 	if err != nil {
 		return Config{}, fmt.Errorf("Error loading env file (godotenv): %v\n", err)
 	}
+	return FromEnv()
+}
 
+// reads the environment variables only, without flags or an env file
+func FromEnv() (Config, error) {
 	var config Config
-	err = env.Parse(&config)
+	err := env.Parse(&config)
 	if err != nil {
 		return Config{}, fmt.Errorf("Error loading env file (carlos0/env): %v\n", err)
 	}
+	if err = config.Validate(); err != nil {
+		return Config{}, err
+	}
 
 	return config, nil
 }
+
+// rejects values the chat can't work with
+func (c Config) Validate() error {
+	if c.PermalinkContext < 0 {
+		return fmt.Errorf("PERMALINK_CONTEXT must not be negative, got %d", c.PermalinkContext)
+	}
+	if c.MaxMessageLength < 1 {
+		return fmt.Errorf("MAX_MESSAGE_LENGTH must be positive, got %d", c.MaxMessageLength)
+	}
+	if c.MaxNicknameLength < 1 {
+		return fmt.Errorf("MAX_NICKNAME_LENGTH must be positive, got %d", c.MaxNicknameLength)
+	}
+	return nil
+}
+
+// returns the defaults of all settings, the environment isn't read.
+// AdminHash has no default: until it is set no admin password is accepted
+func Default() Config {
+	var config Config
+	// an empty value satisfies the required tag
+	env.ParseWithOptions(&config, env.Options{
+		Environment: map[string]string{"ADMIN_PASSWORD_HASH": ""},
+	})
+	return config
+}
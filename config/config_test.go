@@ -0,0 +1,26 @@
+package config
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(*Config)
+		wantErr bool
+	}{
+		{"defaults", func(c *Config) {}, false},
+		{"no permalink context", func(c *Config) { c.PermalinkContext = 0 }, false},
+		{"negative permalink context", func(c *Config) { c.PermalinkContext = -1 }, true},
+		{"zero message length", func(c *Config) { c.MaxMessageLength = 0 }, true},
+		{"negative message length", func(c *Config) { c.MaxMessageLength = -5 }, true},
+		{"zero nickname length", func(c *Config) { c.MaxNicknameLength = 0 }, true},
+		{"one character nicknames", func(c *Config) { c.MaxNicknameLength = 1 }, false},
+	}
+	for _, tt := range tests {
+		c := Default()
+		tt.change(&c)
+		if err := c.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package blobstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/acakp/dumbchat/internal/domain"
+)
+
+// keys are generated by the chat, anything else is rejected
+// so a key can never point outside of the storage directory
+var keyRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+(\.[a-zA-Z0-9]+)?$`)
+
+var errInvalidKey = errors.New("invalid blob key")
+
+// Local stores blobs as files in a single directory
+type Local struct {
+	dir string
+}
+
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("error creating upload dir: %w", err)
+	}
+	return &Local{dir: dir}, nil
+}
+
+func (l *Local) path(key string) (string, error) {
+	if !keyRe.MatchString(key) {
+		return "", errInvalidKey
+	}
+	return filepath.Join(l.dir, key), nil
+}
+
+func (l *Local) Put(key string, r io.Reader) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so readers never see a partial blob
+	tmp, err := os.CreateTemp(l.dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error creating blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing blob: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("error writing blob: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error saving blob: %w", err)
+	}
+	return nil
+}
+
+func (l *Local) Open(key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, domain.ErrNotFound
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening blob: %w", err)
+	}
+	return f, nil
+}
+
+func (l *Local) Delete(key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error deleting blob: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// picks up to limit due deliveries and postpones them by lease,
+// so other instances skip them meanwhile and they are retried
+// if this one dies before finishing
+func ClaimWebhookDeliveries(db *pgxpool.Pool, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	now := time.Now()
+	rows, err := db.Query(context.Background(), `
+		WITH due AS (
+		    SELECT id FROM webhook_deliveries
+		    WHERE status = $1 AND next_attempt_at <= $2
+		    ORDER BY next_attempt_at
+		    LIMIT $3
+		    FOR UPDATE SKIP LOCKED
+		), claimed AS (
+		    UPDATE webhook_deliveries SET next_attempt_at = $4
+		    FROM due WHERE webhook_deliveries.id = due.id
+		    RETURNING webhook_deliveries.*
+		)
+		SELECT `+webhookDeliveryColumns+`
+		FROM claimed d JOIN webhooks w ON w.id = d.webhook_id
+		ORDER BY d.id;
+	`, domain.DeliveryPending, now, limit, now.Add(lease))
+	if err != nil {
+		return nil, fmt.Errorf("error claiming webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &d); err != nil {
+			return nil, fmt.Errorf("error scanning webhook deliveries: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
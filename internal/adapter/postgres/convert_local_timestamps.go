@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// columns of the first schema, stored as timestamp without time zone
+// in the wall clock of the chat server
+var localTimestampColumns = [][2]string{
+	{"messages", "created_at"},
+	{"admin_sessions", "expires_at"},
+}
+
+// converts the columns of the first schema to timestamptz. Old rows are
+// read with the current zone offset of the server, so those from the other
+// side of a DST change end up an hour off
+func convertLocalTimestamps(db *pgxpool.Pool) error {
+	ctx := context.Background()
+	_, offset := time.Now().Zone()
+	for _, c := range localTimestampColumns {
+		table, column := c[0], c[1]
+		var dataType string
+		err := db.QueryRow(ctx, `
+			SELECT data_type FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = $1 AND column_name = $2;
+		`, table, column).Scan(&dataType)
+		if err != nil {
+			return fmt.Errorf("error getting type of %s.%s: %w", table, column, err)
+		}
+		if dataType != "timestamp without time zone" {
+			continue
+		}
+		// DDL takes no parameters, the names and the offset are ours
+		_, err = db.Exec(ctx, fmt.Sprintf(`
+			ALTER TABLE %[1]s ALTER COLUMN %[2]s TYPE timestamptz
+			    USING (%[2]s AT TIME ZONE 'UTC') - make_interval(secs => %[3]d);
+		`, table, column, offset))
+		if err != nil {
+			return fmt.Errorf("error converting %s.%s to timestamptz: %w", table, column, err)
+		}
+	}
+	return nil
+}
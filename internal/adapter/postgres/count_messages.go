@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func CountMessages(db *pgxpool.Pool, filter domain.MessageFilter) (int, error) {
+	where, args := filterClause(filter)
+
+	var count int
+	err := db.QueryRow(context.Background(),
+		"SELECT count(*) FROM messages"+where+";",
+		args...,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting messages: %w", filterError(err))
+	}
+	return count, nil
+}
@@ -6,22 +6,186 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const schema = `
+	CREATE TABLE IF NOT EXISTS messages (
+	    id integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	    nickname text NOT NULL,
+	    content text NOT NULL,
+	    created_at timestamptz NOT NULL
+	);
+	ALTER TABLE messages ADD COLUMN IF NOT EXISTS ip text NOT NULL DEFAULT '';
+	ALTER TABLE messages ADD COLUMN IF NOT EXISTS hidden boolean NOT NULL DEFAULT false;
+	ALTER TABLE messages ADD COLUMN IF NOT EXISTS pinned_at timestamptz;
+	ALTER TABLE messages ADD COLUMN IF NOT EXISTS author_hash text NOT NULL DEFAULT '';
+	ALTER TABLE messages ADD COLUMN IF NOT EXISTS edited_at timestamptz;
+	ALTER TABLE messages ADD COLUMN IF NOT EXISTS reply_to integer
+	    REFERENCES messages(id) ON DELETE SET NULL;
+	CREATE INDEX IF NOT EXISTS messages_reply_to_idx ON messages (reply_to);
+	-- 'simple' config doesn't stem words, so search works the same for any language
+	ALTER TABLE messages ADD COLUMN IF NOT EXISTS search tsvector
+	    GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED;
+	CREATE INDEX IF NOT EXISTS messages_search_idx ON messages USING GIN (search);
+	ALTER TABLE messages ADD COLUMN IF NOT EXISTS verified boolean NOT NULL DEFAULT false;
+	ALTER TABLE messages ADD COLUMN IF NOT EXISTS tripcode text NOT NULL DEFAULT '';
+	ALTER TABLE messages ADD COLUMN IF NOT EXISTS kind text NOT NULL DEFAULT 'user';
+	-- finds the nickname a browser last posted under
+	CREATE INDEX IF NOT EXISTS messages_author_hash_idx ON messages (author_hash, id)
+	    WHERE author_hash <> '';
+
+	-- registered nicknames, names are unique by their look-alike skeleton
+	CREATE TABLE IF NOT EXISTS nicknames (
+	    name_key text PRIMARY KEY,
+	    nickname text NOT NULL,
+	    passphrase_hash text NOT NULL,
+	    created_at timestamptz NOT NULL
+	);
+
+	-- browsers signed in to a registered nickname
+	CREATE TABLE IF NOT EXISTS nickname_sessions (
+	    token_hash text PRIMARY KEY,
+	    name_key text NOT NULL REFERENCES nicknames(name_key) ON UPDATE CASCADE ON DELETE CASCADE,
+	    created_at timestamptz NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS message_edits (
+	    id integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	    message_id integer NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+	    old_content text NOT NULL,
+	    edited_at timestamptz NOT NULL,
+	    edited_by text NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS admin_sessions (
+	    id text PRIMARY KEY,
+	    expires_at timestamptz NOT NULL
+	);
+
+	-- secret URLs external scripts post messages to
+	CREATE TABLE IF NOT EXISTS incoming_webhooks (
+	    id integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	    name text NOT NULL,
+	    token_hash text NOT NULL UNIQUE,
+	    prefix text NOT NULL,
+	    created_at timestamptz NOT NULL,
+	    last_used_at timestamptz
+	);
+
+	-- tokens of bots and integrations, revoked ones are kept for the record
+	CREATE TABLE IF NOT EXISTS api_tokens (
+	    id integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	    name text NOT NULL,
+	    token_hash text NOT NULL UNIQUE,
+	    prefix text NOT NULL,
+	    scopes text[] NOT NULL,
+	    rate_limit integer NOT NULL,
+	    created_at timestamptz NOT NULL,
+	    last_used_at timestamptz,
+	    revoked_at timestamptz
+	);
+
+	CREATE TABLE IF NOT EXISTS webhooks (
+	    id integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	    url text NOT NULL,
+	    secret text NOT NULL,
+	    events text[] NOT NULL,
+	    created_at timestamptz NOT NULL
+	);
+
+	-- outbox of webhook requests, doubles as the delivery log
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+	    id integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	    webhook_id integer NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+	    event text NOT NULL,
+	    payload text NOT NULL,
+	    status text NOT NULL,
+	    attempts integer NOT NULL DEFAULT 0,
+	    next_attempt_at timestamptz NOT NULL,
+	    last_status integer NOT NULL DEFAULT 0,
+	    last_error text NOT NULL DEFAULT '',
+	    created_at timestamptz NOT NULL,
+	    delivered_at timestamptz
+	);
+	CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx
+	    ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+	CREATE TABLE IF NOT EXISTS reports (
+	    id integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	    message_id integer NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+	    reason text NOT NULL,
+	    reporter_ip text NOT NULL,
+	    created_at timestamptz NOT NULL,
+	    UNIQUE (message_id, reporter_ip)
+	);
+
+	CREATE TABLE IF NOT EXISTS bans (
+	    id integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	    ip text NOT NULL UNIQUE,
+	    nickname text NOT NULL,
+	    reason text NOT NULL,
+	    created_at timestamptz NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS reactions (
+	    message_id integer NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+	    emoji text NOT NULL,
+	    reactor text NOT NULL,
+	    created_at timestamptz NOT NULL,
+	    PRIMARY KEY (message_id, emoji, reactor)
+	);
+
+	CREATE TABLE IF NOT EXISTS mentions (
+	    message_id integer NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+	    nickname text NOT NULL,
+	    PRIMARY KEY (message_id, nickname)
+	);
+	CREATE INDEX IF NOT EXISTS mentions_nickname_idx ON mentions (lower(nickname));
+
+	-- rows of deleted messages are kept with message_id set to NULL
+	-- until their blobs are removed from the blob store
+	CREATE TABLE IF NOT EXISTS attachments (
+	    id integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	    message_id integer REFERENCES messages(id) ON DELETE SET NULL,
+	    blob_key text NOT NULL UNIQUE,
+	    thumb_key text NOT NULL DEFAULT '',
+	    filename text NOT NULL,
+	    mime text NOT NULL,
+	    size bigint NOT NULL,
+	    width integer NOT NULL DEFAULT 0,
+	    height integer NOT NULL DEFAULT 0,
+	    created_at timestamptz NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS attachments_message_id_idx ON attachments (message_id);
+	CREATE INDEX IF NOT EXISTS attachments_thumb_key_idx ON attachments (thumb_key);
+
+	CREATE TABLE IF NOT EXISTS link_previews (
+	    url text PRIMARY KEY,
+	    title text NOT NULL DEFAULT '',
+	    description text NOT NULL DEFAULT '',
+	    image_url text NOT NULL DEFAULT '',
+	    site_name text NOT NULL DEFAULT '',
+	    fetched_at timestamptz NOT NULL,
+	    failed boolean NOT NULL DEFAULT false,
+	    image_key text NOT NULL DEFAULT ''
+	);
+
+	CREATE TABLE IF NOT EXISTS announcements (
+	    id integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	    content text NOT NULL,
+	    created_at timestamptz NOT NULL,
+	    expires_at timestamptz
+	);
+
+	-- chat state that outlives restarts, like lockdown mode
+	CREATE TABLE IF NOT EXISTS settings (
+	    key text PRIMARY KEY,
+	    value text NOT NULL
+	);
+`
+
 func CreateTables(db *pgxpool.Pool) error {
-	var query string
-	query = `
-		CREATE TABLE IF NOT EXISTS messages (
-		    id integer GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
-		    nickname text NOT NULL,
-		    content text NOT NULL,
-		    created_at timestamp NOT NULL
-		);
-
-		CREATE TABLE IF NOT EXISTS admin_sessions (
-		    id text PRIMARY KEY,
-		    expires_at timestamp NOT NULL
-		);
-	`
-
-	_, err := db.Exec(context.Background(), query)
-	return err
+	_, err := db.Exec(context.Background(), schema)
+	if err != nil {
+		return err
+	}
+	return convertLocalTimestamps(db)
 }
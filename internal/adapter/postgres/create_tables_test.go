@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// timestamp without time zone stores the wall clock of the server and
+// reads it back as UTC, times are off by the zone offset of the server
+func TestSchemaHasNoLocalTimestamps(t *testing.T) {
+	if cols := regexp.MustCompile(`\w+ timestamp\b`).FindAllString(schema, -1); len(cols) > 0 {
+		t.Errorf("columns without time zone: %q", cols)
+	}
+}
+
+func TestTimestamptzKeepsInstant(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC+9", 9*60*60)
+	defer func() { time.Local = local }()
+
+	m := pgtype.NewMap()
+	now := time.Now()
+	for _, format := range []int16{pgtype.BinaryFormatCode, pgtype.TextFormatCode} {
+		buf, err := m.Encode(pgtype.TimestamptzOID, format, now, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got time.Time
+		if err = m.Scan(pgtype.TimestamptzOID, format, buf, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(now.Truncate(time.Microsecond)) {
+			t.Errorf("format %d: stored %v, read back %v", format, now, got)
+		}
+	}
+}
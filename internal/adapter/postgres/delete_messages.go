@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// deletes all messages matching the filter and returns their IDs.
+// Empty filter is rejected to avoid wiping the whole chat by accident
+func DeleteMessages(db *pgxpool.Pool, filter domain.MessageFilter) ([]int64, error) {
+	if filter.IsEmpty() {
+		return nil, domain.ErrEmptyFilter
+	}
+	where, args := filterClause(filter)
+
+	rows, err := db.Query(context.Background(),
+		"DELETE FROM messages"+where+" RETURNING id;",
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting messages: %w", filterError(err))
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning deleted message id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error deleting messages: %w", filterError(err))
+	}
+	return ids, nil
+}
@@ -0,0 +1,17 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func DeleteNicknameSession(db *pgxpool.Pool, tokenHash string) error {
+	_, err := db.Exec(context.Background(),
+		"DELETE FROM nickname_sessions WHERE token_hash = $1;", tokenHash)
+	if err != nil {
+		return fmt.Errorf("error deleting nickname session: %w", err)
+	}
+	return nil
+}
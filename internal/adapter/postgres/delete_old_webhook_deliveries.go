@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// removes finished deliveries created before the given time
+// from the delivery log
+func DeleteOldWebhookDeliveries(db *pgxpool.Pool, before time.Time) error {
+	_, err := db.Exec(context.Background(), `
+		DELETE FROM webhook_deliveries
+		WHERE status <> $1 AND created_at < $2;
+	`, domain.DeliveryPending, before)
+	if err != nil {
+		return fmt.Errorf("error deleting old webhook deliveries: %w", err)
+	}
+	return nil
+}
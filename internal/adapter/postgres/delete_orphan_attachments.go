@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// removes attachments left behind by deleted messages
+// and returns them, so their blobs can be deleted too
+func DeleteOrphanAttachments(db *pgxpool.Pool) ([]domain.Attachment, error) {
+	rows, err := db.Query(context.Background(), `
+		DELETE FROM attachments
+		WHERE message_id IS NULL
+		RETURNING `+attachmentColumns+`;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting orphan attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []domain.Attachment
+	for rows.Next() {
+		var a domain.Attachment
+		if err := scanAttachment(rows, &a); err != nil {
+			return nil, fmt.Errorf("error scanning attachment: %w", err)
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func DeleteReports(db *pgxpool.Pool, messageID int64) error {
+	_, err := db.Exec(context.Background(),
+		"DELETE FROM reports WHERE message_id = $1;",
+		messageID,
+	)
+	if err != nil {
+		return fmt.Errorf("error deleting reports: %w", err)
+	}
+	return nil
+}
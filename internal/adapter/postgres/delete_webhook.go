@@ -0,0 +1,20 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func DeleteWebhook(db *pgxpool.Pool, id int64) error {
+	res, err := db.Exec(context.Background(), "DELETE FROM webhooks WHERE id = $1;", id)
+	if err != nil {
+		return fmt.Errorf("error deleting webhook: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
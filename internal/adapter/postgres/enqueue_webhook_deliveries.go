@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// queues the payload for every webhook subscribed to the event,
+// returns the number of queued deliveries
+func EnqueueWebhookDeliveries(db *pgxpool.Pool, event, payload string, at time.Time) (int64, error) {
+	res, err := db.Exec(context.Background(), `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, status, next_attempt_at, created_at)
+		SELECT id, $1, $2, $3, $4, $4
+		FROM webhooks
+		WHERE $1 = ANY(events);
+	`, event, payload, domain.DeliveryPending, at)
+	if err != nil {
+		return 0, fmt.Errorf("error queueing webhook deliveries: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns announcements that haven't expired yet, newest first
+func GetAnnouncements(db *pgxpool.Pool) ([]domain.Announcement, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT id, content, created_at, expires_at
+		FROM announcements
+		WHERE expires_at IS NULL OR expires_at > $1
+		ORDER BY created_at DESC;
+	`, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error getting announcements from db: %w", err)
+	}
+	defer rows.Close()
+
+	var announcements []domain.Announcement
+	for rows.Next() {
+		var a domain.Announcement
+		if err := rows.Scan(&a.ID, &a.Content, &a.CreatedAt, &a.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("error scanning announcements: %w", err)
+		}
+		announcements = append(announcements, a)
+	}
+	return announcements, nil
+}
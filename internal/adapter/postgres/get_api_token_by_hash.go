@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// revoked tokens are returned too, callers must check IsRevoked
+func GetAPITokenByHash(db *pgxpool.Pool, tokenHash string) (domain.APIToken, error) {
+	var t domain.APIToken
+	err := scanAPIToken(db.QueryRow(context.Background(), `
+		SELECT `+apiTokenColumns+`
+		FROM api_tokens WHERE token_hash = $1;
+	`, tokenHash), &t)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.APIToken{}, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return domain.APIToken{}, fmt.Errorf("error getting API token: %w", err)
+	}
+	return t, nil
+}
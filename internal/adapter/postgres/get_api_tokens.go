@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns all tokens, active ones first
+func GetAPITokens(db *pgxpool.Pool) ([]domain.APIToken, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT `+apiTokenColumns+`
+		FROM api_tokens
+		ORDER BY revoked_at IS NOT NULL, created_at DESC;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting API tokens from db: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []domain.APIToken
+	for rows.Next() {
+		var t domain.APIToken
+		if err := scanAPIToken(rows, &t); err != nil {
+			return nil, fmt.Errorf("error scanning API tokens: %w", err)
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, rows.Err()
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// finds an attachment by its file or thumbnail key.
+// Attachments of deleted messages aren't returned, those of hidden ones only to admins
+func GetAttachment(db *pgxpool.Pool, key string, isAdmin bool) (domain.Attachment, error) {
+	row := db.QueryRow(context.Background(), `
+		SELECT `+attachmentColumns+`
+		FROM attachments
+		WHERE (blob_key = $1 OR (thumb_key = $1 AND thumb_key <> ''))
+		  AND EXISTS (
+		      SELECT 1 FROM messages m
+		      WHERE m.id = attachments.message_id AND ($2 OR NOT m.hidden)
+		  );
+	`, key, isAdmin)
+
+	var a domain.Attachment
+	err := scanAttachment(row, &a)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Attachment{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Attachment{}, fmt.Errorf("error getting attachment from db: %w", err)
+	}
+	return a, nil
+}
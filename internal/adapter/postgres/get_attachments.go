@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns attachments of the given messages
+func GetAttachments(db *pgxpool.Pool, messageIDs []int64) (map[int64][]domain.Attachment, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT `+attachmentColumns+`
+		FROM attachments
+		WHERE message_id = ANY($1)
+		ORDER BY id;
+	`, messageIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error getting attachments from db: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := make(map[int64][]domain.Attachment)
+	for rows.Next() {
+		var a domain.Attachment
+		if err := scanAttachment(rows, &a); err != nil {
+			return nil, fmt.Errorf("error scanning attachment: %w", err)
+		}
+		attachments[a.MessageID] = append(attachments[a.MessageID], a)
+	}
+	return attachments, rows.Err()
+}
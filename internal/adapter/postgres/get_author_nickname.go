@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns the nickname of the latest message posted with the given
+// author token hash, domain.ErrNotFound if there's none
+func GetAuthorNickname(db *pgxpool.Pool, authorHash string) (string, error) {
+	var nickname string
+	err := db.QueryRow(context.Background(), `
+		SELECT nickname FROM messages
+		WHERE author_hash = $1 AND kind <> $2
+		ORDER BY id DESC
+		LIMIT 1;
+	`, authorHash, domain.MessageKindBot).Scan(&nickname)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", domain.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("error getting author nickname: %w", err)
+	}
+	return nickname, nil
+}
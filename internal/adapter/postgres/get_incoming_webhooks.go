@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func GetIncomingWebhooks(db *pgxpool.Pool) ([]domain.IncomingWebhook, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT id, name, prefix, created_at, last_used_at
+		FROM incoming_webhooks
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting incoming webhooks from db: %w", err)
+	}
+	defer rows.Close()
+
+	var hooks []domain.IncomingWebhook
+	for rows.Next() {
+		var h domain.IncomingWebhook
+		if err := rows.Scan(&h.ID, &h.Name, &h.Prefix, &h.CreatedAt, &h.LastUsedAt); err != nil {
+			return nil, fmt.Errorf("error scanning incoming webhooks: %w", err)
+		}
+		hooks = append(hooks, h)
+	}
+	return hooks, rows.Err()
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns cached previews for the given URLs, keyed by URL
+func GetLinkPreviews(db *pgxpool.Pool, urls []string) (map[string]domain.LinkPreview, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT url, title, description, image_url, image_key, site_name, fetched_at, failed
+		FROM link_previews
+		WHERE url = ANY($1);
+	`, urls)
+	if err != nil {
+		return nil, fmt.Errorf("error getting link previews from db: %w", err)
+	}
+	defer rows.Close()
+
+	previews := make(map[string]domain.LinkPreview)
+	for rows.Next() {
+		var p domain.LinkPreview
+		err := rows.Scan(&p.URL, &p.Title, &p.Description, &p.ImageURL, &p.ImageKey, &p.SiteName, &p.FetchedAt, &p.Failed)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning link preview: %w", err)
+		}
+		previews[p.URL] = p
+	}
+	return previews, rows.Err()
+}
@@ -10,7 +10,7 @@ import (
 
 func GetMessage(db *pgxpool.Pool, messageID int) (domain.Message, error) {
 	rows, err := db.Query(context.Background(), `
-		SELECT id, nickname, content, created_at
+		SELECT `+messageColumns+`
 		FROM messages WHERE id=$1;
 	`, messageID)
 	if err != nil {
@@ -19,10 +19,11 @@ func GetMessage(db *pgxpool.Pool, messageID int) (domain.Message, error) {
 	defer rows.Close()
 
 	var msg domain.Message
-	for rows.Next() {
-		if err := rows.Scan(&msg.ID, &msg.Nickname, &msg.Content, &msg.CreatedAt); err != nil {
-			return domain.Message{}, fmt.Errorf("error scanning message: %w", err)
-		}
+	if !rows.Next() {
+		return domain.Message{}, domain.ErrMessageNotFound
+	}
+	if err := scanMessage(rows, &msg); err != nil {
+		return domain.Message{}, fmt.Errorf("error scanning message: %w", err)
 	}
 	return msg, nil
 }
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns previous versions of a message, oldest first
+func GetMessageEdits(db *pgxpool.Pool, messageID int64) ([]domain.MessageEdit, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT id, message_id, old_content, edited_at, edited_by
+		FROM message_edits
+		WHERE message_id = $1
+		ORDER BY edited_at, id;
+	`, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting message edits from db: %w", err)
+	}
+	defer rows.Close()
+
+	var edits []domain.MessageEdit
+	for rows.Next() {
+		var e domain.MessageEdit
+		if err := rows.Scan(&e.ID, &e.MessageID, &e.OldContent, &e.EditedAt, &e.EditedBy); err != nil {
+			return nil, fmt.Errorf("error scanning message edits: %w", err)
+		}
+		edits = append(edits, e)
+	}
+	return edits, nil
+}
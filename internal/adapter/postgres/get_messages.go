@@ -8,11 +8,14 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func GetMessages(db *pgxpool.Pool) ([]domain.Message, error) {
+// hidden (auto-moderated) messages are only returned when includeHidden is set
+func GetMessages(db *pgxpool.Pool, includeHidden bool) ([]domain.Message, error) {
 	rows, err := db.Query(context.Background(), `
-		SELECT id, nickname, content, created_at
-		FROM messages;
-	`)
+		SELECT `+messageColumns+`
+		FROM messages
+		WHERE $1 OR NOT hidden
+		ORDER BY id;
+	`, includeHidden)
 	if err != nil {
 		return []domain.Message{}, fmt.Errorf("error getting MESSAGES from db: %w", err)
 	}
@@ -21,7 +24,7 @@ func GetMessages(db *pgxpool.Pool) ([]domain.Message, error) {
 	var messages []domain.Message
 	for rows.Next() {
 		var m domain.Message
-		if err := rows.Scan(&m.ID, &m.Nickname, &m.Content, &m.CreatedAt); err != nil {
+		if err := scanMessage(rows, &m); err != nil {
 			return []domain.Message{}, fmt.Errorf("error scanning MESSAGES from db: %w", err)
 		}
 		messages = append(messages, m)
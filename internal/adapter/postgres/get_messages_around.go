@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns the message with up to n messages before and after it, oldest first
+func GetMessagesAround(db *pgxpool.Pool, messageID int64, n int, includeHidden bool) ([]domain.Message, error) {
+	rows, err := db.Query(context.Background(), `
+		(SELECT `+messageColumns+`
+		FROM messages
+		WHERE messages.id <= $1 AND ($3 OR NOT messages.hidden)
+		ORDER BY messages.id DESC
+		LIMIT $2 + 1)
+		UNION ALL
+		(SELECT `+messageColumns+`
+		FROM messages
+		WHERE messages.id > $1 AND ($3 OR NOT messages.hidden)
+		ORDER BY messages.id
+		LIMIT $2)
+		ORDER BY 1;
+	`, messageID, n, includeHidden)
+	if err != nil {
+		return nil, fmt.Errorf("error getting messages around %d from db: %w", messageID, err)
+	}
+	defer rows.Close()
+
+	var messages []domain.Message
+	for rows.Next() {
+		var m domain.Message
+		if err := scanMessage(rows, &m); err != nil {
+			return nil, fmt.Errorf("error scanning messages from db: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns domain.ErrNotFound for nicknames nobody has registered
+func GetNicknamePassphraseHash(db *pgxpool.Pool, nickname string) (string, error) {
+	var hash string
+	err := db.QueryRow(context.Background(),
+		"SELECT passphrase_hash FROM nicknames WHERE name_key = $1;",
+		nameKey(nickname),
+	).Scan(&hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", domain.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("error getting nickname: %w", err)
+	}
+	return hash, nil
+}
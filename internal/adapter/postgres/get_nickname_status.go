@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// reports whether the nickname is registered and whether
+// the browser with the given identity token hash is signed in to it
+func GetNicknameStatus(db *pgxpool.Pool, nickname, tokenHash string) (registered, owned bool, err error) {
+	err = db.QueryRow(context.Background(), `
+		SELECT
+		    EXISTS (SELECT 1 FROM nicknames WHERE name_key = $1),
+		    EXISTS (SELECT 1 FROM nickname_sessions WHERE name_key = $1 AND token_hash = $2);
+	`, nameKey(nickname), tokenHash).Scan(&registered, &owned)
+	if err != nil {
+		return false, false, fmt.Errorf("error checking nickname: %w", err)
+	}
+	return registered, owned, nil
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns pinned messages, most recently pinned first
+func GetPinnedMessages(db *pgxpool.Pool) ([]domain.Message, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT `+messageColumns+`
+		FROM messages
+		WHERE pinned_at IS NOT NULL AND NOT hidden
+		ORDER BY pinned_at DESC;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting pinned messages from db: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []domain.Message
+	for rows.Next() {
+		var m domain.Message
+		if err := scanMessage(rows, &m); err != nil {
+			return nil, fmt.Errorf("error scanning pinned messages: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	return messages, nil
+}
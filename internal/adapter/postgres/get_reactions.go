@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns aggregated reaction counts for the given messages.
+// Mine is set for reactions made by reactor
+func GetReactions(db *pgxpool.Pool, messageIDs []int64, reactor string) (map[int64][]domain.ReactionCount, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT message_id, emoji, count(*), bool_or(reactor = $2)
+		FROM reactions
+		WHERE message_id = ANY($1)
+		GROUP BY message_id, emoji
+		ORDER BY message_id, min(created_at);
+	`, messageIDs, reactor)
+	if err != nil {
+		return nil, fmt.Errorf("error getting reactions from db: %w", err)
+	}
+	defer rows.Close()
+
+	reactions := make(map[int64][]domain.ReactionCount)
+	for rows.Next() {
+		var messageID int64
+		var rc domain.ReactionCount
+		if err := rows.Scan(&messageID, &rc.Emoji, &rc.Count, &rc.Mine); err != nil {
+			return nil, fmt.Errorf("error scanning reactions: %w", err)
+		}
+		reactions[messageID] = append(reactions[messageID], rc)
+	}
+	return reactions, nil
+}
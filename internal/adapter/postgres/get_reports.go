@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns reported messages, most recently reported first
+func GetReports(db *pgxpool.Pool) ([]domain.ReportedMessage, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT r.id, r.reason, r.reporter_ip, r.created_at, `+messageColumns+`
+		FROM reports r
+		JOIN messages ON messages.id = r.message_id
+		ORDER BY max(r.created_at) OVER (PARTITION BY messages.id) DESC, messages.id, r.created_at;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting reports from db: %w", err)
+	}
+	defer rows.Close()
+
+	var reported []domain.ReportedMessage
+	for rows.Next() {
+		var m domain.Message
+		var r domain.Report
+		fields := append([]any{&r.ID, &r.Reason, &r.ReporterIP, &r.CreatedAt}, messageFields(&m)...)
+		err := rows.Scan(fields...)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning reports: %w", err)
+		}
+		r.MessageID = m.ID
+
+		last := len(reported) - 1
+		if last < 0 || reported[last].Msg.ID != m.ID {
+			reported = append(reported, domain.ReportedMessage{Msg: m})
+			last++
+		}
+		reported[last].Reports = append(reported[last].Reports, r)
+	}
+	return reported, nil
+}
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns the registered nickname the browser with the given identity
+// token hash is signed in to, domain.ErrNotFound if there's none
+func GetSessionNickname(db *pgxpool.Pool, tokenHash string) (string, error) {
+	var nickname string
+	err := db.QueryRow(context.Background(), `
+		SELECT n.nickname
+		FROM nickname_sessions s
+		JOIN nicknames n ON n.name_key = s.name_key
+		WHERE s.token_hash = $1;
+	`, tokenHash).Scan(&nickname)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", domain.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("error getting session nickname: %w", err)
+	}
+	return nickname, nil
+}
@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns the saved value of a setting, ok is false if it was never saved
+func GetSetting(db *pgxpool.Pool, key string) (value string, ok bool, err error) {
+	err = db.QueryRow(context.Background(),
+		"SELECT value FROM settings WHERE key = $1;", key,
+	).Scan(&value)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("error getting setting %s: %w", key, err)
+	}
+	return value, true, nil
+}
@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns the root message followed by all its direct and nested replies.
+// Without includeHidden a hidden root isn't found, and replies
+// are only followed through visible messages
+func GetThread(db *pgxpool.Pool, rootID int64, includeHidden bool) ([]domain.Message, error) {
+	rows, err := db.Query(context.Background(), `
+		WITH RECURSIVE thread AS (
+		    SELECT id FROM messages WHERE id = $1 AND ($2 OR NOT hidden)
+		    UNION
+		    SELECT m.id FROM messages m JOIN thread t ON m.reply_to = t.id
+		    WHERE $2 OR NOT m.hidden
+		)
+		SELECT `+messageColumns+`
+		FROM messages
+		WHERE id IN (SELECT id FROM thread) AND ($2 OR NOT hidden)
+		ORDER BY id;
+	`, rootID, includeHidden)
+	if err != nil {
+		return nil, fmt.Errorf("error getting thread from db: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []domain.Message
+	for rows.Next() {
+		var m domain.Message
+		if err := scanMessage(rows, &m); err != nil {
+			return nil, fmt.Errorf("error scanning thread: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	if len(messages) == 0 {
+		return nil, domain.ErrMessageNotFound
+	}
+	return messages, nil
+}
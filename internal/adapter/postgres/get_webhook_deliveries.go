@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns up to limit newest deliveries with the given status, any if empty
+func GetWebhookDeliveries(db *pgxpool.Pool, status string, limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+		WHERE $1 = '' OR d.status = $1
+		ORDER BY d.id DESC
+		LIMIT $2;
+	`, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting webhook deliveries from db: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &d); err != nil {
+			return nil, fmt.Errorf("error scanning webhook deliveries: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
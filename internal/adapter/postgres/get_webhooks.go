@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func GetWebhooks(db *pgxpool.Pool) ([]domain.Webhook, error) {
+	rows, err := db.Query(context.Background(), `
+		SELECT id, url, secret, events, created_at
+		FROM webhooks
+		ORDER BY id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting webhooks from db: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []domain.Webhook
+	for rows.Next() {
+		var w domain.Webhook
+		if err := rows.Scan(&w.ID, &w.URL, &w.Secret, &w.Events, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning webhooks: %w", err)
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
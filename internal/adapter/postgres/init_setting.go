@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// saves the value unless the setting exists already,
+// and returns the one that's kept
+func InitSetting(db *pgxpool.Pool, key, value string) (string, error) {
+	var kept string
+	err := db.QueryRow(context.Background(), `
+		WITH inserted AS (
+		    INSERT INTO settings (key, value) VALUES ($1, $2)
+		    ON CONFLICT (key) DO NOTHING
+		    RETURNING value
+		)
+		SELECT value FROM inserted
+		UNION ALL
+		SELECT value FROM settings WHERE key = $1
+		LIMIT 1;
+	`, key, value).Scan(&kept)
+	if err != nil {
+		return "", fmt.Errorf("error initializing setting %s: %w", key, err)
+	}
+	return kept, nil
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func InsertAnnouncement(db *pgxpool.Pool, a domain.Announcement) (int64, error) {
+	var id int64
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO announcements (content, created_at, expires_at)
+		VALUES ($1, $2, $3) RETURNING id;
+	`, a.Content, a.CreatedAt, a.ExpiresAt).Scan(&id)
+	if err != nil {
+		return -1, fmt.Errorf("error inserting announcement to db: %w", err)
+	}
+	return id, nil
+}
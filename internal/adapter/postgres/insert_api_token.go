@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func InsertAPIToken(db *pgxpool.Pool, t domain.APIToken, tokenHash string) (int64, error) {
+	var id int64
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO api_tokens (name, token_hash, prefix, scopes, rate_limit, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id;
+	`, t.Name, tokenHash, t.Prefix, t.Scopes, t.RateLimit, t.CreatedAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting API token: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func InsertAttachment(db *pgxpool.Pool, a domain.Attachment) (int64, error) {
+	query := `
+	INSERT INTO attachments (message_id, blob_key, thumb_key, filename, mime, size, width, height, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id;
+	`
+	var id int64
+	err := db.QueryRow(context.Background(), query,
+		a.MessageID, a.Key, a.ThumbKey, a.Filename, a.MIME, a.Size, a.Width, a.Height, a.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return -1, fmt.Errorf("error inserting attachment to db: %w", err)
+	}
+	return id, nil
+}
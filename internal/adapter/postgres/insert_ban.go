@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func InsertBan(db *pgxpool.Pool, ban domain.Ban) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO bans (ip, nickname, reason, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (ip) DO UPDATE
+		SET nickname = EXCLUDED.nickname, reason = EXCLUDED.reason;
+	`, ban.IP, ban.Nickname, ban.Reason, ban.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error inserting ban to db: %w", err)
+	}
+	return nil
+}
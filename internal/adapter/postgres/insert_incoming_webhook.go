@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func InsertIncomingWebhook(db *pgxpool.Pool, hook domain.IncomingWebhook, tokenHash string) (int64, error) {
+	var id int64
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO incoming_webhooks (name, token_hash, prefix, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id;
+	`, hook.Name, tokenHash, hook.Prefix, hook.CreatedAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting incoming webhook: %w", err)
+	}
+	return id, nil
+}
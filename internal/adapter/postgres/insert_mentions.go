@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func InsertMentions(db *pgxpool.Pool, messageID int64, nicknames []string) error {
+	if len(nicknames) == 0 {
+		return nil
+	}
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO mentions (message_id, nickname)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT DO NOTHING;
+	`, messageID, nicknames)
+	if err != nil {
+		return fmt.Errorf("error inserting mentions to db: %w", err)
+	}
+	return nil
+}
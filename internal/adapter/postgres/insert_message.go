@@ -10,8 +10,8 @@ import (
 
 func InsertMessage(db *pgxpool.Pool, msg domain.Message) (int64, error) {
 	query := `
-	INSERT INTO messages (nickname, content, created_at)
-	VALUES ($1, $2, $3) RETURNING id;
+	INSERT INTO messages (nickname, content, created_at, ip, author_hash, reply_to, verified, tripcode, kind)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id;
 	`
 	var msgID int
 	err := db.QueryRow(
@@ -20,6 +20,12 @@ func InsertMessage(db *pgxpool.Pool, msg domain.Message) (int64, error) {
 		msg.Nickname,
 		msg.Content,
 		msg.CreatedAt,
+		msg.IP,
+		msg.AuthorHash,
+		msg.ReplyTo,
+		msg.Verified,
+		msg.Tripcode,
+		msg.Kind,
 	).Scan(&msgID)
 	if err != nil {
 		return -1, fmt.Errorf("error inserting messages to db: %w", err)
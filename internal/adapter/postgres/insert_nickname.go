@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// registers a nickname, returns false if it's already taken
+func InsertNickname(db *pgxpool.Pool, nickname, passphraseHash string) (bool, error) {
+	res, err := db.Exec(context.Background(), `
+		INSERT INTO nicknames (name_key, nickname, passphrase_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name_key) DO NOTHING;
+	`, nameKey(nickname), nickname, passphraseHash, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("error inserting nickname: %w", err)
+	}
+	return res.RowsAffected() == 1, nil
+}
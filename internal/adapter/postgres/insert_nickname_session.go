@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// signs a browser in to the nickname,
+// a browser holds a single identity at a time
+func InsertNicknameSession(db *pgxpool.Pool, tokenHash, nickname string) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO nickname_sessions (token_hash, name_key, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (token_hash) DO UPDATE SET
+		    name_key = EXCLUDED.name_key,
+		    created_at = EXCLUDED.created_at;
+	`, tokenHash, nameKey(nickname), time.Now())
+	if err != nil {
+		return fmt.Errorf("error saving nickname session: %w", err)
+	}
+	return nil
+}
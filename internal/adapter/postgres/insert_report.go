@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// saves the report and returns the total number of reports
+// filed against the message. Every reporter (IP) can report
+// a message only once, repeated reports return ErrAlreadyReported
+func InsertReport(db *pgxpool.Pool, report domain.Report) (int, error) {
+	res, err := db.Exec(context.Background(), `
+		INSERT INTO reports (message_id, reason, reporter_ip, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (message_id, reporter_ip) DO NOTHING;
+	`, report.MessageID, report.Reason, report.ReporterIP, report.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting report to db: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return 0, domain.ErrAlreadyReported
+	}
+
+	var count int
+	err = db.QueryRow(context.Background(),
+		"SELECT count(*) FROM reports WHERE message_id = $1;",
+		report.MessageID,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting reports: %w", err)
+	}
+	return count, nil
+}
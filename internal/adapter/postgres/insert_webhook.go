@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func InsertWebhook(db *pgxpool.Pool, w domain.Webhook) (int64, error) {
+	var id int64
+	err := db.QueryRow(context.Background(), `
+		INSERT INTO webhooks (url, secret, events, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id;
+	`, w.URL, w.Secret, w.Events, w.CreatedAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting webhook: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,20 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func IsBanned(db *pgxpool.Pool, ip string) (bool, error) {
+	var banned bool
+	err := db.QueryRow(context.Background(),
+		"SELECT EXISTS (SELECT 1 FROM bans WHERE ip = $1);",
+		ip,
+	).Scan(&banned)
+	if err != nil {
+		return false, fmt.Errorf("error checking ban in db: %w", err)
+	}
+	return banned, nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns up to q.Limit messages next to the cursor, oldest first.
+// Without an After cursor the newest messages are returned
+func ListMessages(db *pgxpool.Pool, q domain.MessagePageQuery, includeHidden bool) ([]domain.Message, error) {
+	conds := []string{"($1 OR NOT hidden)"}
+	args := []any{includeHidden}
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if q.Before > 0 {
+		add("id < $%d", q.Before)
+	}
+	if q.After > 0 {
+		add("id > $%d", q.After)
+	}
+	if q.Nickname != "" {
+		add("lower(nickname) = lower($%d)", q.Nickname)
+	}
+	if !q.From.IsZero() {
+		add("created_at >= $%d", q.From)
+	}
+	if !q.To.IsZero() {
+		add("created_at <= $%d", q.To)
+	}
+	order := "DESC"
+	if q.After > 0 {
+		order = "ASC"
+	}
+	args = append(args, q.Limit)
+
+	rows, err := db.Query(context.Background(), `
+		SELECT `+messageColumns+`
+		FROM messages
+		WHERE `+strings.Join(conds, " AND ")+`
+		ORDER BY id `+order+`
+		LIMIT $`+fmt.Sprint(len(args))+`;
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []domain.Message
+	for rows.Next() {
+		var m domain.Message
+		if err := scanMessage(rows, &m); err != nil {
+			return nil, fmt.Errorf("error scanning message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	if order == "DESC" {
+		slices.Reverse(messages)
+	}
+	return messages, rows.Err()
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE of regular expressions postgres can't compile
+const invalidRegexCode = "2201B"
+
+// builds WHERE clause with positional arguments for the given filter
+func filterClause(f domain.MessageFilter) (string, []any) {
+	var conds []string
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.Nickname != "" {
+		add("nickname = $%d", f.Nickname)
+	}
+	if f.IP != "" {
+		add("ip = $%d", f.IP)
+	}
+	if !f.From.IsZero() {
+		add("created_at >= $%d", f.From)
+	}
+	if !f.To.IsZero() {
+		add("created_at <= $%d", f.To)
+	}
+	if f.Pattern != "" {
+		add("content ~ $%d", f.Pattern)
+	}
+	if len(f.IDs) > 0 {
+		add("id = ANY($%d)", f.IDs)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// patterns are validated with RE2, which accepts some
+// that postgres rejects; those are returned as domain.ErrInvalidPattern
+func filterError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == invalidRegexCode {
+		return fmt.Errorf("%w: %s", domain.ErrInvalidPattern, pgErr.Message)
+	}
+	return err
+}
@@ -0,0 +1,9 @@
+package postgres
+
+import "github.com/acakp/dumbchat/pkg/textutil"
+
+// registered nicknames are unique by skeleton, so a look-alike
+// of a registered nickname is taken too
+func nameKey(nickname string) string {
+	return textutil.Skeleton(nickname)
+}
@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// queues a dead delivery again with a fresh set of retries
+func RetryWebhookDelivery(db *pgxpool.Pool, id int64) error {
+	res, err := db.Exec(context.Background(), `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = 0, next_attempt_at = $3
+		WHERE id = $1 AND status = $4;
+	`, id, domain.DeliveryPending, time.Now(), domain.DeliveryDead)
+	if err != nil {
+		return fmt.Errorf("error retrying webhook delivery: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// revoking a revoked token keeps the original revocation time
+func RevokeAPIToken(db *pgxpool.Pool, id int64) (domain.APIToken, error) {
+	var t domain.APIToken
+	err := scanAPIToken(db.QueryRow(context.Background(), `
+		UPDATE api_tokens SET revoked_at = COALESCE(revoked_at, $2)
+		WHERE id = $1
+		RETURNING `+apiTokenColumns+`;
+	`, id, time.Now()), &t)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.APIToken{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.APIToken{}, fmt.Errorf("error revoking API token: %w", err)
+	}
+	return t, nil
+}
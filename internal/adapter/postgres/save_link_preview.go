@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// inserts or refreshes a cached link preview
+func SaveLinkPreview(db *pgxpool.Pool, p domain.LinkPreview) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO link_previews (url, title, description, image_url, image_key, site_name, fetched_at, failed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (url) DO UPDATE SET
+		    title = EXCLUDED.title,
+		    description = EXCLUDED.description,
+		    image_url = EXCLUDED.image_url,
+		    image_key = EXCLUDED.image_key,
+		    site_name = EXCLUDED.site_name,
+		    fetched_at = EXCLUDED.fetched_at,
+		    failed = EXCLUDED.failed;
+	`, p.URL, p.Title, p.Description, p.ImageURL, p.ImageKey, p.SiteName, p.FetchedAt, p.Failed)
+	if err != nil {
+		return fmt.Errorf("error saving link preview: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package postgres
+
+import (
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const apiTokenColumns = `id, name, prefix, scopes, rate_limit, created_at, last_used_at, revoked_at`
+
+func scanAPIToken(row pgx.Row, t *domain.APIToken) error {
+	return row.Scan(&t.ID, &t.Name, &t.Prefix, &t.Scopes, &t.RateLimit, &t.CreatedAt, &t.LastUsedAt, &t.RevokedAt)
+}
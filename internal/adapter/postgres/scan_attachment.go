@@ -0,0 +1,16 @@
+package postgres
+
+import (
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const attachmentColumns = `id, COALESCE(message_id, 0), blob_key, thumb_key,
+	filename, mime, size, width, height, created_at`
+
+func scanAttachment(row pgx.Row, a *domain.Attachment) error {
+	return row.Scan(
+		&a.ID, &a.MessageID, &a.Key, &a.ThumbKey,
+		&a.Filename, &a.MIME, &a.Size, &a.Width, &a.Height, &a.CreatedAt,
+	)
+}
@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// columns of messages table in the order expected by messageFields
+const messageColumns = `messages.id, messages.nickname, messages.content, messages.created_at,
+	messages.ip, messages.hidden, messages.pinned_at IS NOT NULL,
+	messages.author_hash, messages.edited_at, messages.reply_to, messages.verified,
+	messages.tripcode, messages.kind,
+	COALESCE((SELECT p.nickname FROM messages p WHERE p.id = messages.reply_to), ''),
+	COALESCE((SELECT left(p.content, 100) FROM messages p WHERE p.id = messages.reply_to), ''),
+	COALESCE((SELECT p.hidden FROM messages p WHERE p.id = messages.reply_to), false)`
+
+// scan destinations for messageColumns
+func messageFields(m *domain.Message) []any {
+	return []any{
+		&m.ID, &m.Nickname, &m.Content, &m.CreatedAt, &m.IP, &m.Hidden, &m.Pinned,
+		&m.AuthorHash, &m.EditedAt, &m.ReplyTo, &m.Verified, &m.Tripcode, &m.Kind,
+		&m.ReplyPreview.Nickname, &m.ReplyPreview.Content, &m.ReplyPreview.Hidden,
+	}
+}
+
+func scanMessage(row pgx.Row, m *domain.Message) error {
+	return row.Scan(messageFields(m)...)
+}
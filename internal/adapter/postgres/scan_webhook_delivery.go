@@ -0,0 +1,16 @@
+package postgres
+
+import (
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// columns of webhook_deliveries joined with webhooks
+// in the order expected by scanWebhookDelivery
+const webhookDeliveryColumns = `d.id, d.webhook_id, w.url, w.secret, d.event, d.payload, d.status,
+	d.attempts, d.next_attempt_at, d.last_status, d.last_error, d.created_at, d.delivered_at`
+
+func scanWebhookDelivery(row pgx.Row, d *domain.WebhookDelivery) error {
+	return row.Scan(&d.ID, &d.WebhookID, &d.URL, &d.Secret, &d.Event, &d.Payload, &d.Status,
+		&d.Attempts, &d.NextAttemptAt, &d.LastStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt)
+}
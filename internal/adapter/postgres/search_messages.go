@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns up to limit newest messages matching the query,
+// with fragments around the matched words
+func SearchMessages(db *pgxpool.Pool, q domain.SearchQuery, includeHidden bool, limit int) ([]domain.SearchResult, error) {
+	conds := []string{"($2 OR NOT messages.hidden)"}
+	args := []any{q.Text, includeHidden}
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if q.Text != "" {
+		conds = append(conds, "messages.search @@ query")
+	}
+	if q.Nickname != "" {
+		add("lower(messages.nickname) = lower($%d)", q.Nickname)
+	}
+	if !q.From.IsZero() {
+		add("messages.created_at >= $%d", q.From)
+	}
+	if !q.To.IsZero() {
+		add("messages.created_at <= $%d", q.To)
+	}
+	args = append(args, limit)
+
+	rows, err := db.Query(context.Background(), `
+		SELECT `+messageColumns+`,
+		    CASE WHEN $1 = '' THEN left(messages.content, 200)
+		    ELSE ts_headline('simple', messages.content, query,
+		        'StartSel=`+domain.SearchMarkStart+`, StopSel=`+domain.SearchMarkStop+`, MaxWords=30, MinWords=10, MaxFragments=2')
+		    END
+		FROM messages, websearch_to_tsquery('simple', $1) query
+		WHERE `+strings.Join(conds, " AND ")+`
+		ORDER BY messages.id DESC
+		LIMIT $`+fmt.Sprint(len(args))+`;
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching messages: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.SearchResult
+	for rows.Next() {
+		var res domain.SearchResult
+		if err := rows.Scan(append(messageFields(&res.Msg), &res.Fragment)...); err != nil {
+			return nil, fmt.Errorf("error scanning search result: %w", err)
+		}
+		results = append(results, res)
+	}
+	return results, rows.Err()
+}
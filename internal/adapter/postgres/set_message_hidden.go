@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func SetMessageHidden(db *pgxpool.Pool, messageID int64, hidden bool) error {
+	res, err := db.Exec(context.Background(),
+		"UPDATE messages SET hidden = $2 WHERE id = $1;",
+		messageID, hidden,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating message visibility: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrMessageNotFound
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func SetMessagePinned(db *pgxpool.Pool, messageID int64, pinned bool) error {
+	var pinnedAt *time.Time
+	if pinned {
+		now := time.Now()
+		pinnedAt = &now
+	}
+	// re-pinning keeps the original pin time
+	res, err := db.Exec(context.Background(), `
+		UPDATE messages SET pinned_at = CASE WHEN $2::timestamptz IS NULL THEN NULL ELSE COALESCE(pinned_at, $2) END
+		WHERE id = $1;
+	`, messageID, pinnedAt)
+	if err != nil {
+		return fmt.Errorf("error updating message pin: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrMessageNotFound
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func SetSetting(db *pgxpool.Pool, key, value string) error {
+	_, err := db.Exec(context.Background(), `
+		INSERT INTO settings (key, value) VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value;
+	`, key, value)
+	if err != nil {
+		return fmt.Errorf("error saving setting %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// adds the reaction, or removes it if the reactor already reacted
+// with the same emoji. Returns true if the reaction was added.
+// A single statement, so concurrent toggles can't interleave
+func ToggleReaction(db *pgxpool.Pool, reaction domain.Reaction) (bool, error) {
+	var added bool
+	err := db.QueryRow(context.Background(), `
+		WITH removed AS (
+		    DELETE FROM reactions
+		    WHERE message_id = $1 AND emoji = $2 AND reactor = $3
+		    RETURNING 1
+		), added AS (
+		    INSERT INTO reactions (message_id, emoji, reactor, created_at)
+		    SELECT $1::integer, $2::text, $3::text, $4::timestamptz
+		    WHERE NOT EXISTS (SELECT 1 FROM removed)
+		    ON CONFLICT DO NOTHING
+		    RETURNING 1
+		)
+		SELECT EXISTS (SELECT 1 FROM added);
+	`, reaction.MessageID, reaction.Emoji, reaction.Reactor, reaction.CreatedAt).Scan(&added)
+	if err != nil {
+		return false, fmt.Errorf("error toggling reaction: %w", err)
+	}
+	return added, nil
+}
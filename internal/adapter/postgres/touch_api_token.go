@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// updates last_used_at at most once per minute to spare writes
+// on busy tokens
+func TouchAPIToken(db *pgxpool.Pool, id int64) error {
+	now := time.Now()
+	_, err := db.Exec(context.Background(), `
+		UPDATE api_tokens SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3);
+	`, id, now, now.Add(-time.Minute))
+	if err != nil {
+		return fmt.Errorf("error updating API token usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// replaces message content and keeps the previous version in message_edits
+func UpdateMessageContent(db *pgxpool.Pool, edit domain.MessageEdit, content string) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var oldContent string
+	err = tx.QueryRow(ctx,
+		"SELECT content FROM messages WHERE id = $1 FOR UPDATE;",
+		edit.MessageID,
+	).Scan(&oldContent)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrMessageNotFound
+		}
+		return fmt.Errorf("error getting message content: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO message_edits (message_id, old_content, edited_at, edited_by)
+		VALUES ($1, $2, $3, $4);
+	`, edit.MessageID, oldContent, edit.EditedAt, edit.EditedBy)
+	if err != nil {
+		return fmt.Errorf("error saving message edit: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		"UPDATE messages SET content = $2, edited_at = $3 WHERE id = $1;",
+		edit.MessageID, content, edit.EditedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating message content: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// saves the outcome of a delivery attempt
+func UpdateWebhookDelivery(db *pgxpool.Pool, d domain.WebhookDelivery) error {
+	_, err := db.Exec(context.Background(), `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4,
+		    last_status = $5, last_error = $6, delivered_at = $7
+		WHERE id = $1;
+	`, d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.LastStatus, d.LastError, d.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("error updating webhook delivery: %w", err)
+	}
+	return nil
+}
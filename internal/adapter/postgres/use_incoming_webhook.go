@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// looks up the hook by its token hash and records its use
+func UseIncomingWebhook(db *pgxpool.Pool, tokenHash string) (domain.IncomingWebhook, error) {
+	var h domain.IncomingWebhook
+	err := db.QueryRow(context.Background(), `
+		UPDATE incoming_webhooks SET last_used_at = $2
+		WHERE token_hash = $1
+		RETURNING id, name, prefix, created_at, last_used_at;
+	`, tokenHash, time.Now()).Scan(&h.ID, &h.Name, &h.Prefix, &h.CreatedAt, &h.LastUsedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.IncomingWebhook{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.IncomingWebhook{}, fmt.Errorf("error getting incoming webhook: %w", err)
+	}
+	return h, nil
+}
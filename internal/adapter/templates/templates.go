@@ -12,6 +12,9 @@ type ParsedTemplates struct {
 	ChatTmpl    *template.Template
 	MessageTmpl *template.Template
 	LoginTmpl   *template.Template
+	AdminTmpl   *template.Template
+	PinnedTmpl  *template.Template
+	SearchTmpl  *template.Template
 }
 
 func ParseTemplatesCmd() ParsedTemplates {
@@ -19,26 +22,26 @@ func ParseTemplatesCmd() ParsedTemplates {
 	layoutTmpl, err := layoutTmpl.Parse(web.LayoutHTML)
 	if err != nil {
 		err = fmt.Errorf("error parsing templates in ParseTemplatesCmd: %w", err)
-		return ParsedTemplates{err, nil, nil, nil}
+		return ParsedTemplates{Err: err}
 	}
 	return ParseTemplates(layoutTmpl)
 }
 
 func ParseTemplates(t *template.Template) ParsedTemplates {
 	var ret ParsedTemplates
-	_, err := t.Parse(web.ChatHTML)
-	_, err = t.Parse(web.MessageHTML)
-	if err != nil {
-		err = fmt.Errorf("error parsing templates: %w", err)
-		return ParsedTemplates{err, nil, nil, nil}
+	for _, html := range []string{web.ChatHTML, web.MessageHTML, web.PinnedHTML, web.SearchHTML} {
+		if _, err := t.Parse(html); err != nil {
+			err = fmt.Errorf("error parsing templates: %w", err)
+			return ParsedTemplates{Err: err}
+		}
 	}
 	ret.ChatTmpl = t
 
 	messageTmpl := template.New("msg")
-	messageTmpl, err = messageTmpl.Parse(web.MessageHTML)
+	messageTmpl, err := messageTmpl.Parse(web.MessageHTML)
 	if err != nil {
 		err = fmt.Errorf("error parsing message template: %w", err)
-		return ParsedTemplates{err, nil, nil, nil}
+		return ParsedTemplates{Err: err}
 	}
 	ret.MessageTmpl = messageTmpl
 
@@ -46,9 +49,33 @@ func ParseTemplates(t *template.Template) ParsedTemplates {
 	loginTmpl, err = loginTmpl.Parse(web.LoginHTML)
 	if err != nil {
 		err = fmt.Errorf("error parsing login template: %w", err)
-		return ParsedTemplates{err, nil, nil, nil}
+		return ParsedTemplates{Err: err}
 	}
 	ret.LoginTmpl = loginTmpl
 
+	adminTmpl := template.New("admin")
+	adminTmpl, err = adminTmpl.Parse(web.AdminHTML)
+	if err != nil {
+		err = fmt.Errorf("error parsing admin template: %w", err)
+		return ParsedTemplates{Err: err}
+	}
+	ret.AdminTmpl = adminTmpl
+
+	pinnedTmpl := template.New("pinned")
+	pinnedTmpl, err = pinnedTmpl.Parse(web.PinnedHTML)
+	if err != nil {
+		err = fmt.Errorf("error parsing pinned template: %w", err)
+		return ParsedTemplates{Err: err}
+	}
+	ret.PinnedTmpl = pinnedTmpl
+
+	searchTmpl := template.New("search")
+	searchTmpl, err = searchTmpl.Parse(web.SearchHTML)
+	if err != nil {
+		err = fmt.Errorf("error parsing search template: %w", err)
+		return ParsedTemplates{Err: err}
+	}
+	ret.SearchTmpl = searchTmpl
+
 	return ret
 }
@@ -1,18 +1,22 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
 
 	"github.com/acakp/dumbchat/config"
+	"github.com/acakp/dumbchat/internal/adapter/blobstore"
 	"github.com/acakp/dumbchat/internal/adapter/postgres"
 	"github.com/acakp/dumbchat/internal/adapter/templates"
 	httpctrl "github.com/acakp/dumbchat/internal/controller/http"
 	v1 "github.com/acakp/dumbchat/internal/controller/http/v1"
 	"github.com/acakp/dumbchat/internal/controller/ws"
+	"github.com/acakp/dumbchat/internal/usecase"
 	"github.com/acakp/dumbchat/web"
 	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
 )
 
 func Run(cfg config.Config) error {
@@ -39,7 +43,18 @@ func Run(cfg config.Config) error {
 	hub := ws.New()
 	go hub.Run()
 
-	handler := v1.New(cfg, dbpool, hub, &ts)
+	blobs, err := blobstore.NewLocal(cfg.UploadDir)
+	if err != nil {
+		return fmt.Errorf("blobstore.NewLocal: %w", err)
+	}
+	// blobs of messages deleted while the server was down
+	usecase.CleanupAttachments(log.Logger.WithContext(context.Background()), dbpool, blobs)
+
+	handler := v1.New(cfg, dbpool, hub, &ts, blobs)
+	if err = handler.RestoreState(); err != nil {
+		return fmt.Errorf("handler.RestoreState: %w", err)
+	}
+	go handler.RunWebhooks(context.Background())
 
 	r.Route(cfg.BasePath, func(r chi.Router) {
 		httpctrl.RegisterRoutes(r, handler)
@@ -8,12 +8,12 @@ import (
 	"github.com/acakp/dumbchat/pkg/logger"
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/hlog"
-	"github.com/rs/zerolog/log"
 )
 
 func RegisterRoutes(r chi.Router, h *v1.Handler) {
-	r.Use(hlog.NewHandler(log.Logger))
+	r.Use(hlog.NewHandler(h.Log))
 	r.Use(logger.Middleware)
+	r.Use(h.Authenticate)
 
 	r.Get("/", h.Chat)
 	r.Post("/messages", h.Messages)
@@ -22,4 +22,44 @@ func RegisterRoutes(r chi.Router, h *v1.Handler) {
 	r.Post("/admin/login", h.AdminPost)
 	r.Get("/ws", ws.HandleWS(h.Hub))
 	r.Get("/message/{messageID}", h.RenderMessage)
+	r.Post("/messages/{messageID}/report", h.ReportMessage)
+	r.Get("/messages/{messageID}/edit", h.EditMessageForm)
+	r.Put("/messages/{messageID}", h.EditMessage)
+	r.Get("/messages/{messageID}", h.Permalink)
+	r.Get("/messages/{messageID}/edits", h.MessageEdits)
+	r.Delete("/messages/{messageID}/own", h.DeleteOwnMessage)
+	r.Get("/thread/{messageID}", h.Thread)
+	r.Post("/messages/{messageID}/reactions", h.React)
+	r.Get("/attachments/{key}", h.Attachment)
+	r.Get("/previews/{key}", h.PreviewImage)
+	r.Get("/search", h.Search)
+	r.Post("/nicknames", h.ClaimNickname)
+	r.Delete("/nicknames/session", h.SignOutNickname)
+	r.Get("/admin", v1.RequireAdminSession(h.DBPool, http.HandlerFunc(h.Admin)))
+	r.Delete("/admin/reports/{messageID}", v1.RequireAdmin(h.DBPool, http.HandlerFunc(h.DismissReports)))
+	r.Post("/admin/bans", v1.RequireAdmin(h.DBPool, http.HandlerFunc(h.BanUser)))
+	r.Post("/admin/bulk-delete", v1.RequireAdmin(h.DBPool, http.HandlerFunc(h.BulkDelete)))
+	r.Post("/admin/lockdown", v1.RequireAdmin(h.DBPool, http.HandlerFunc(h.Lockdown)))
+	r.Get("/pinned", h.Pinned)
+	r.Post("/messages/{messageID}/pin", v1.RequireAdmin(h.DBPool, http.HandlerFunc(h.PinMessage)))
+	r.Delete("/messages/{messageID}/pin", v1.RequireAdmin(h.DBPool, http.HandlerFunc(h.UnpinMessage)))
+	r.Post("/admin/announcements", v1.RequireAdmin(h.DBPool, http.HandlerFunc(h.CreateAnnouncement)))
+	r.Delete("/admin/announcements/{announcementID}", v1.RequireAdmin(h.DBPool, http.HandlerFunc(h.DeleteAnnouncement)))
+
+	r.Post("/admin/tokens", v1.RequireAdminSession(h.DBPool, http.HandlerFunc(h.CreateAPIToken)))
+	r.Delete("/admin/tokens/{tokenID}", v1.RequireAdminSession(h.DBPool, http.HandlerFunc(h.RevokeAPIToken)))
+	r.Post("/admin/webhooks", v1.RequireAdminSession(h.DBPool, http.HandlerFunc(h.CreateWebhook)))
+	r.Delete("/admin/webhooks/{webhookID}", v1.RequireAdminSession(h.DBPool, http.HandlerFunc(h.DeleteWebhook)))
+	r.Post("/admin/webhooks/deliveries/{deliveryID}/retry", v1.RequireAdminSession(h.DBPool, http.HandlerFunc(h.RetryWebhookDelivery)))
+	r.Post("/hooks/{token}", h.PostIncomingWebhook)
+	r.Post("/admin/hooks", v1.RequireAdminSession(h.DBPool, http.HandlerFunc(h.CreateIncomingWebhook)))
+	r.Delete("/admin/hooks/{hookID}", v1.RequireAdminSession(h.DBPool, http.HandlerFunc(h.DeleteIncomingWebhook)))
+	r.Route(v1.APIPrefix, func(r chi.Router) {
+		r.NotFound(h.APINotFound)
+		r.MethodNotAllowed(h.APIMethodNotAllowed)
+		r.Get("/openapi.json", h.OpenAPI)
+		for _, route := range h.APIRoutes() {
+			r.Method(route.Method, route.Pattern, h.APIHandler(route))
+		}
+	})
 }
@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// webhook deliveries shown on the admin page
+const deliveryLogSize = 50
+
+func (h *Handler) Admin(w http.ResponseWriter, r *http.Request) {
+	reports, err := postgres.GetReports(h.DBPool)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load reports")
+		return
+	}
+
+	announcements, err := postgres.GetAnnouncements(h.DBPool)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load announcements")
+		return
+	}
+
+	tokens, err := postgres.GetAPITokens(h.DBPool)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load API tokens")
+		return
+	}
+
+	webhooks, err := postgres.GetWebhooks(h.DBPool)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load webhooks")
+		return
+	}
+	deliveries, err := postgres.GetWebhookDeliveries(h.DBPool, "", deliveryLogSize)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load webhook deliveries")
+		return
+	}
+	dead, err := postgres.GetWebhookDeliveries(h.DBPool, domain.DeliveryDead, deliveryLogSize)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load webhook deliveries")
+		return
+	}
+
+	incoming, err := postgres.GetIncomingWebhooks(h.DBPool)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load webhooks")
+		return
+	}
+	hookViews := make([]domain.IncomingWebhookView, 0, len(incoming))
+	for _, hook := range incoming {
+		hookViews = append(hookViews, domain.IncomingWebhookView{Hook: hook, URLs: h.URLs})
+	}
+
+	tokenViews := make([]domain.APITokenView, 0, len(tokens))
+	for _, t := range tokens {
+		tokenViews = append(tokenViews, domain.APITokenView{Token: t, URLs: h.URLs})
+	}
+
+	view := domain.AdminView{
+		Reports:        reports,
+		Announcements:  announcements,
+		APITokens:      tokenViews,
+		Webhooks:       webhooks,
+		IncomingHooks:  hookViews,
+		Deliveries:     deliveries,
+		DeadDeliveries: dead,
+		ReadOnly:       h.Hub.ReadOnly(),
+		URLs:           h.URLs,
+	}
+	err = h.Tmpls.AdminTmpl.Execute(w, view)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load admin template")
+		return
+	}
+}
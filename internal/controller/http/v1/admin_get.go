@@ -9,7 +9,7 @@ import (
 func (h *Handler) AdminGet(w http.ResponseWriter, r *http.Request) {
 	err := h.Tmpls.LoginTmpl.Execute(w, nil)
 	if err != nil {
-		render.Error(w, err, http.StatusInternalServerError, "Internal Server Error")
+		render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
 		return
 	}
 }
@@ -14,7 +14,7 @@ func (h *Handler) AdminPost(w http.ResponseWriter, r *http.Request) {
 	// parse form data
 	err := r.ParseForm()
 	if err != nil {
-		render.Error(w, err, http.StatusBadRequest, "Error parsing form")
+		render.Error(w, r, err, http.StatusBadRequest, "Error parsing form")
 		return
 	}
 	// extract form value
@@ -24,9 +24,9 @@ func (h *Handler) AdminPost(w http.ResponseWriter, r *http.Request) {
 	sessionID, err := postgres.CheckAdminPassword(h.DBPool, pwd, h.Cfg.AdminHash)
 	if err != nil {
 		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			render.Error(w, err, http.StatusUnauthorized, "Authentication Error")
+			render.Error(w, r, err, http.StatusUnauthorized, "Authentication Error")
 		} else {
-			render.Error(w, err, http.StatusInternalServerError, "Internal Server Error")
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
 		}
 		return
 	}
@@ -0,0 +1,49 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+func (h *Handler) CreateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	a, err := usecase.ParseAnnouncement(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	a.ID, err = postgres.InsertAnnouncement(h.DBPool, a)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to save announcement")
+		return
+	}
+
+	h.broadcast("pin", domain.PinEvent{Kind: domain.PinKindAnnouncement, ID: a.ID})
+	http.Redirect(w, r, h.URLs.Admin, http.StatusSeeOther)
+}
+
+func (h *Handler) DeleteAnnouncement(w http.ResponseWriter, r *http.Request) {
+	id, err := usecase.ExtractAnnouncementID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	err = postgres.DeleteAnnouncement(h.DBPool, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Announcement not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+
+	h.broadcast("unpin", domain.PinEvent{Kind: domain.PinKindAnnouncement, ID: id})
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/openapi"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// mount point of the JSON API relative to the chat base path
+const APIPrefix = "/api/v1"
+
+// endpoint of the JSON API. The same table registers the routes
+// and generates the OpenAPI document, so they can't diverge
+type APIRoute struct {
+	Method  string
+	Pattern string
+	// operationId in the OpenAPI document
+	Name    string
+	Summary string
+	Handler http.HandlerFunc
+	// required from API tokens. Without a token only moderate
+	// endpoints are restricted, to admin sessions
+	Scope  string
+	Params []openapi.Parameter
+	// zero values of the request and response body types, nil if there is no body
+	Request  any
+	Response any
+	// status of a successful response
+	Status int
+	// bodies of other successful responses by status
+	Alternatives map[int]any
+}
+
+var messageIDParam = openapi.Parameter{
+	Name: "messageID", In: "path", Required: true,
+	Schema: &openapi.Schema{Type: "integer", Format: "int64"},
+}
+
+func queryParam(name, description string, schema openapi.Schema) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: &schema}
+}
+
+func (h *Handler) APIRoutes() []APIRoute {
+	id := openapi.Schema{Type: "integer", Format: "int64"}
+	dateTime := openapi.Schema{Type: "string", Format: "date-time"}
+
+	return []APIRoute{
+		{
+			Method: http.MethodGet, Pattern: "/messages", Name: "listMessages",
+			Summary: "List messages, the newest page unless a cursor is given",
+			Handler: h.APIListMessages,
+			Scope:   domain.ScopeRead,
+			Params: []openapi.Parameter{
+				queryParam("before", "only messages older than this ID", id),
+				queryParam("after", "only messages newer than this ID", id),
+				queryParam("limit", "page size, 1 to 200, 50 by default", openapi.Schema{Type: "integer"}),
+				queryParam("nickname", "only messages by this nickname, case-insensitive", openapi.Schema{Type: "string"}),
+				queryParam("from", "only messages posted at or after this time", dateTime),
+				queryParam("to", "only messages posted at or before this time", dateTime),
+			},
+			Response: domain.APIMessagePage{},
+			Status:   http.StatusOK,
+		},
+		{
+			Method: http.MethodGet, Pattern: "/messages/{messageID}", Name: "getMessage",
+			Summary:  "Get a message",
+			Handler:  h.APIGetMessage,
+			Scope:    domain.ScopeRead,
+			Params:   []openapi.Parameter{messageIDParam},
+			Response: domain.APIMessage{},
+			Status:   http.StatusOK,
+		},
+		{
+			Method: http.MethodPost, Pattern: "/messages", Name: "postMessage",
+			Summary: "Post a message. Files are attached by posting the chat form as multipart/form-data instead. " +
+				"Content starting with / runs a slash command, commands that don't post a message answer with 200",
+			Handler:      h.APIPostMessage,
+			Scope:        domain.ScopePost,
+			Request:      domain.APINewMessage{},
+			Response:     domain.APIMessage{},
+			Status:       http.StatusCreated,
+			Alternatives: map[int]any{http.StatusOK: domain.CommandReply{}},
+		},
+		{
+			Method: http.MethodDelete, Pattern: "/messages/{messageID}", Name: "deleteMessage",
+			Summary: "Delete a message",
+			Handler: h.APIDeleteMessage,
+			Scope:   domain.ScopeModerate,
+			Params:  []openapi.Parameter{messageIDParam},
+			Status:  http.StatusNoContent,
+		},
+	}
+}
+
+// wraps the route handler with the access checks
+func (h *Handler) APIHandler(route APIRoute) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if t, ok := usecase.APITokenFrom(r.Context()); ok {
+			if !t.HasScope(route.Scope) {
+				render.JSONError(w, r, domain.ErrMissingScope, http.StatusForbidden,
+					fmt.Sprintf("The API token lacks the %q scope", route.Scope))
+				return
+			}
+		} else if route.Scope == domain.ScopeModerate && !usecase.IsAdmin(h.DBPool, r) {
+			render.JSONError(w, r, nil, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		route.Handler(w, r)
+	}
+}
+
+func (h *Handler) APINotFound(w http.ResponseWriter, r *http.Request) {
+	render.JSONError(w, r, nil, http.StatusNotFound, "Not found")
+}
+
+func (h *Handler) APIMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	render.JSONError(w, r, nil, http.StatusMethodNotAllowed, "Method not allowed")
+}
+
+// serves the OpenAPI document describing APIRoutes
+func (h *Handler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, http.StatusOK, h.OpenAPIDocument())
+}
+
+func (h *Handler) OpenAPIDocument() *openapi.Document {
+	doc := openapi.New("dumbchat", "1")
+	doc.Servers = []openapi.Server{{URL: h.URLs.Base + APIPrefix}}
+	doc.Components.SecuritySchemes = map[string]openapi.SecurityScheme{
+		"adminSession": {Type: "apiKey", In: "cookie", Name: "admin_session"},
+		"apiToken":     {Type: "http", Scheme: "bearer", Description: "token issued on the admin page"},
+	}
+	errorBody := doc.SchemaOf(render.ErrorBody{})
+
+	for _, route := range h.APIRoutes() {
+		op := openapi.Operation{
+			Summary:     route.Summary,
+			Description: fmt.Sprintf("API tokens need the `%s` scope.", route.Scope),
+			OperationID: route.Name,
+			Parameters:  route.Params,
+			Responses: map[string]openapi.Response{
+				"default": {
+					Description: "error",
+					Content:     map[string]openapi.MediaType{"application/json": {Schema: errorBody}},
+				},
+			},
+		}
+		if route.Request != nil {
+			op.RequestBody = &openapi.RequestBody{
+				Required: true,
+				Content:  map[string]openapi.MediaType{"application/json": {Schema: doc.SchemaOf(route.Request)}},
+			}
+		}
+		ok := openapi.Response{Description: http.StatusText(route.Status)}
+		if route.Response != nil {
+			ok.Content = map[string]openapi.MediaType{"application/json": {Schema: doc.SchemaOf(route.Response)}}
+		}
+		op.Responses[strconv.Itoa(route.Status)] = ok
+		for status, body := range route.Alternatives {
+			op.Responses[strconv.Itoa(status)] = openapi.Response{
+				Description: http.StatusText(status),
+				Content:     map[string]openapi.MediaType{"application/json": {Schema: doc.SchemaOf(body)}},
+			}
+		}
+		if route.Scope == domain.ScopeModerate {
+			op.Security = []map[string][]string{{"adminSession": {}}, {"apiToken": {}}}
+		} else {
+			// an empty requirement makes authentication optional
+			op.Security = []map[string][]string{{}, {"apiToken": {}}}
+		}
+		doc.AddOperation(route.Pattern, route.Method, op)
+	}
+	return doc
+}
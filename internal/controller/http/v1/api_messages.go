@@ -0,0 +1,99 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+func (h *Handler) APIListMessages(w http.ResponseWriter, r *http.Request) {
+	q, err := usecase.ParseMessagePageQuery(r)
+	if err != nil {
+		render.JSONError(w, r, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	viewer := usecase.GetViewer(h.DBPool, r, h.Cfg.SiteSecret)
+	page, err := usecase.ListMessages(h.DBPool, q, viewer, h.URLs)
+	if err != nil {
+		render.JSONError(w, r, err, http.StatusInternalServerError, "Failed to load messages")
+		return
+	}
+	render.JSON(w, r, http.StatusOK, page)
+}
+
+func (h *Handler) APIGetMessage(w http.ResponseWriter, r *http.Request) {
+	messageID, err := usecase.ExtractMessageID(r)
+	if err != nil {
+		render.JSONError(w, r, err, http.StatusBadRequest, "Invalid message ID")
+		return
+	}
+
+	viewer := usecase.GetViewer(h.DBPool, r, h.Cfg.SiteSecret)
+	msg, err := postgres.GetMessage(h.DBPool, messageID)
+	if err == nil && msg.Hidden && !viewer.IsAdmin {
+		err = domain.ErrMessageNotFound
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			render.JSONError(w, r, err, http.StatusNotFound, "Message not found")
+		} else {
+			render.JSONError(w, r, err, http.StatusInternalServerError, "Failed to load message")
+		}
+		return
+	}
+	h.renderAPIMessage(w, r, http.StatusOK, msg, viewer)
+}
+
+func (h *Handler) APIPostMessage(w http.ResponseWriter, r *http.Request) {
+	h.limitMessageBody(w, r)
+	msg, err := usecase.ParseAPIMessage(r, h.Cfg.SiteSecret)
+	if err != nil {
+		renderJSONError(w, r, parseError(err))
+		return
+	}
+
+	msg, reply, err := h.runCommand(r, msg)
+	if err != nil {
+		renderJSONError(w, r, err)
+		return
+	}
+	if reply != nil && msg.Content == "" {
+		render.JSON(w, r, http.StatusOK, reply)
+		return
+	}
+
+	msg, err = h.createMessage(w, r, msg)
+	if err != nil {
+		renderJSONError(w, r, err)
+		return
+	}
+	viewer := usecase.GetViewer(h.DBPool, r, h.Cfg.SiteSecret)
+	h.renderAPIMessage(w, r, http.StatusCreated, msg, viewer)
+}
+
+func (h *Handler) APIDeleteMessage(w http.ResponseWriter, r *http.Request) {
+	messageID, err := usecase.ExtractMessageID(r)
+	if err != nil {
+		render.JSONError(w, r, err, http.StatusBadRequest, "Invalid message ID")
+		return
+	}
+	if err = h.deleteMessage(messageID); err != nil {
+		renderJSONError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) renderAPIMessage(w http.ResponseWriter, r *http.Request, status int, msg domain.Message, viewer domain.Viewer) {
+	msgs, err := usecase.NewAPIMessages(h.DBPool, []domain.Message{msg}, viewer, h.URLs)
+	if err != nil {
+		render.JSONError(w, r, err, http.StatusInternalServerError, "Failed to load message")
+		return
+	}
+	render.JSON(w, r, status, msgs[0])
+}
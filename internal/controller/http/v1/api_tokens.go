@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// renders the new token once, it can't be shown again later
+func (h *Handler) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	t, err := usecase.ParseAPIToken(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	t, secret, err := usecase.CreateAPIToken(h.DBPool, t)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to create API token")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	view := domain.APITokenView{Token: t, Secret: secret, URLs: h.URLs}
+	err = h.Tmpls.AdminTmpl.ExecuteTemplate(w, "api-token-created", view)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load admin template")
+		return
+	}
+}
+
+func (h *Handler) RevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	id, err := usecase.ExtractAPITokenID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	t, err := postgres.RevokeAPIToken(h.DBPool, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "API token not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = h.Tmpls.AdminTmpl.ExecuteTemplate(w, "api-token", domain.APITokenView{Token: t, URLs: h.URLs})
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load admin template")
+		return
+	}
+}
@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+	"github.com/go-chi/chi/v5"
+)
+
+// serves an attached file or its thumbnail
+func (h *Handler) Attachment(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	a, err := postgres.GetAttachment(h.DBPool, key, usecase.IsAdmin(h.DBPool, r))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "File not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+
+	blob, err := h.Blobs.Open(key)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "File not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+	defer blob.Close()
+
+	contentType := a.MIME
+	if key == a.ThumbKey {
+		contentType = mime.TypeByExtension(path.Ext(key))
+	}
+	w.Header().Set("Content-Type", contentType)
+	// uploads are never rendered as documents of this site
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	// keys are random and never reused, but the message may get hidden,
+	// so shared caches mustn't keep the file
+	w.Header().Set("Cache-Control", "private, max-age=31536000, immutable")
+	if !a.IsImage() {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	}
+	io.Copy(w, blob)
+}
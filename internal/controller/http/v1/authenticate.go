@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+	"golang.org/x/time/rate"
+)
+
+// authenticates requests carrying an API token in the Authorization header
+// and applies the token's rate limit. Requests without a token pass as is
+func (h *Handler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret, ok := usecase.BearerToken(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		t, err := usecase.AuthenticateAPIToken(h.DBPool, secret)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidToken) {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				render.JSONError(w, r, err, http.StatusUnauthorized, "Invalid or revoked API token")
+			} else {
+				render.JSONError(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+			}
+			return
+		}
+
+		limit := rate.Limit(float64(t.RateLimit) / 60)
+		if !h.tokenLimiter.AllowN(strconv.FormatInt(t.ID, 10), limit, t.RateLimit) {
+			// time until the next request is allowed
+			w.Header().Set("Retry-After", strconv.Itoa((60+t.RateLimit-1)/t.RateLimit))
+			render.JSONError(w, r, errors.New("API token rate limit"), http.StatusTooManyRequests, "Rate limit exceeded")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(usecase.WithAPIToken(r.Context(), t)))
+	})
+}
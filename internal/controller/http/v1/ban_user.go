@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// bans the IP address the given message was posted from
+func (h *Handler) BanUser(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Error parsing form")
+		return
+	}
+	messageID, err := strconv.Atoi(r.FormValue("message_id"))
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	msg, err := postgres.GetMessage(h.DBPool, messageID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Message not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+	if err = h.banAuthor(msg, r.FormValue("reason")); err != nil {
+		if errors.Is(err, domain.ErrUnknownIP) {
+			render.Error(w, r, err, http.StatusBadRequest, "Author IP is unknown")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Failed to ban user")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprint(w, `<span class="banned">banned</span>`)
+}
+
+// bans the IP address msg was posted from
+func (h *Handler) banAuthor(msg domain.Message, reason string) error {
+	if msg.IP == "" {
+		return domain.ErrUnknownIP
+	}
+	ban := domain.Ban{
+		IP:        msg.IP,
+		Nickname:  msg.Nickname,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	if err := postgres.InsertBan(h.DBPool, ban); err != nil {
+		return err
+	}
+	h.dispatchWebhooks(domain.EventBan, domain.BanEvent{
+		MessageID: msg.ID,
+		Nickname:  ban.Nickname,
+		Reason:    ban.Reason,
+		CreatedAt: ban.CreatedAt,
+	})
+	return nil
+}
@@ -0,0 +1,226 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/command"
+)
+
+const (
+	// most messages /clear deletes by count
+	maxClearCount = 500
+	// longest /slow interval
+	maxSlowMode = time.Hour
+)
+
+func (h *Handler) builtinCommands() []command.Command {
+	return []command.Command{
+		{
+			Name: "help", Description: "list the commands",
+			Run: h.helpCommand,
+		},
+		{
+			Name: "me", Usage: "<text>", Description: "post an action, e.g. /me waves",
+			Run: meCommand,
+		},
+		{
+			Name: "nick", Usage: "<nickname>", Description: "change your nickname",
+			Run: h.nickCommand,
+		},
+		{
+			Name: "ban", Usage: "<nickname | message ID> [reason]",
+			Description: "ban the author of a message, by default the latest one of the nickname",
+			Admin:       true,
+			Run:         h.banCommand,
+		},
+		{
+			Name: "clear", Usage: "<count | nickname>",
+			Description: fmt.Sprintf("delete the newest messages, up to %d, or all messages of a nickname", maxClearCount),
+			Admin:       true,
+			Run:         h.clearCommand,
+		},
+		{
+			Name: "slow", Usage: "[seconds | off]",
+			Description: "let visitors post one message per interval",
+			Admin:       true,
+			Run:         h.slowCommand,
+		},
+		{
+			Name: "lock", Usage: "[on | off]",
+			Description: "switch read-only mode, toggles it without an argument",
+			Admin:       true,
+			Run:         h.lockCommand,
+		},
+	}
+}
+
+func (h *Handler) helpCommand(ctx context.Context, call command.Call) (command.Result, error) {
+	var b strings.Builder
+	b.WriteString("Commands:")
+	for _, c := range h.Commands.Commands(call.IsAdmin) {
+		fmt.Fprintf(&b, "\n%s", c.Help())
+		if c.Description != "" {
+			fmt.Fprintf(&b, " - %s", c.Description)
+		}
+	}
+	return command.Result{Reply: b.String()}, nil
+}
+
+func meCommand(ctx context.Context, call command.Call) (command.Result, error) {
+	if call.Args == "" {
+		return command.Result{}, command.ErrUsage
+	}
+	return command.Result{Post: call.Args, Action: true}, nil
+}
+
+func (h *Handler) nickCommand(ctx context.Context, call command.Call) (command.Result, error) {
+	if call.Args == "" {
+		return command.Result{}, command.ErrUsage
+	}
+	// the tripcode secret is kept, it's only checked when posting
+	name, _, _ := strings.Cut(call.Args, "#")
+	name = strings.TrimSpace(name)
+
+	if err := usecase.ValidateNicknameFormat(name, h.Cfg.MaxNicknameLength); err != nil {
+		return command.Result{}, command.Errorf("%s", err.Error())
+	}
+	if !call.IsAdmin {
+		if err := usecase.ValidateNickname(domain.Message{Nickname: name}, h.Cfg.BannedNicknames); err != nil {
+			return command.Result{}, command.Errorf("Nickname contains prohibited words")
+		}
+	}
+	if _, err := usecase.CheckNicknameOwner(h.DBPool, call.Request, name, h.Cfg.SiteSecret); err != nil {
+		if errors.Is(err, domain.ErrNicknameReserved) {
+			return command.Result{}, command.Errorf("%s is registered, sign in with its passphrase to use it", name)
+		}
+		return command.Result{}, err
+	}
+	h.Hub.SetClientNickname(usecase.ClientSocket(call.Request), name)
+	return command.Result{
+		Reply:    fmt.Sprintf("You are now %s", name),
+		Nickname: call.Args,
+	}, nil
+}
+
+func (h *Handler) banCommand(ctx context.Context, call command.Call) (command.Result, error) {
+	target, reason, _ := strings.Cut(call.Args, " ")
+	if target == "" {
+		return command.Result{}, command.ErrUsage
+	}
+
+	var msg domain.Message
+	if id, err := strconv.Atoi(strings.TrimPrefix(target, "#")); err == nil {
+		msg, err = postgres.GetMessage(h.DBPool, id)
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			return command.Result{}, command.Errorf("Message %d not found", id)
+		}
+		if err != nil {
+			return command.Result{}, err
+		}
+	} else {
+		nickname := strings.TrimPrefix(target, "@")
+		latest, err := postgres.ListMessages(h.DBPool, domain.MessagePageQuery{Nickname: nickname, Limit: 1}, true)
+		if err != nil {
+			return command.Result{}, err
+		}
+		if len(latest) == 0 {
+			return command.Result{}, command.Errorf("%s hasn't posted anything", nickname)
+		}
+		msg = latest[0]
+	}
+
+	if err := h.banAuthor(msg, strings.TrimSpace(reason)); err != nil {
+		if errors.Is(err, domain.ErrUnknownIP) {
+			return command.Result{}, command.Errorf("The IP of %s is unknown", msg.Nickname)
+		}
+		return command.Result{}, err
+	}
+	return command.Result{Reply: fmt.Sprintf("Banned %s", msg.Nickname)}, nil
+}
+
+func (h *Handler) clearCommand(ctx context.Context, call command.Call) (command.Result, error) {
+	if call.Args == "" {
+		return command.Result{}, command.ErrUsage
+	}
+
+	var filter domain.MessageFilter
+	if count, err := strconv.Atoi(call.Args); err == nil {
+		if count < 1 || count > maxClearCount {
+			return command.Result{}, command.Errorf("Count must be between 1 and %d", maxClearCount)
+		}
+		newest, err := postgres.ListMessages(h.DBPool, domain.MessagePageQuery{Limit: count}, true)
+		if err != nil {
+			return command.Result{}, err
+		}
+		if len(newest) == 0 {
+			return command.Result{Reply: "There are no messages"}, nil
+		}
+		// by ID, messages posted since the page was read are kept
+		for _, msg := range newest {
+			filter.IDs = append(filter.IDs, msg.ID)
+		}
+	} else {
+		filter.Nickname = strings.TrimPrefix(call.Args, "@")
+	}
+
+	ids, err := h.deleteMessages(filter)
+	if err != nil {
+		return command.Result{}, err
+	}
+	return command.Result{Reply: fmt.Sprintf("%d messages deleted", len(ids))}, nil
+}
+
+func (h *Handler) slowCommand(ctx context.Context, call command.Call) (command.Result, error) {
+	if call.Args == "" {
+		if d := h.slowMode.Interval(); d > 0 {
+			return command.Result{Reply: fmt.Sprintf("Slow mode: one message per %d seconds", int(d.Seconds()))}, nil
+		}
+		return command.Result{Reply: "Slow mode is off"}, nil
+	}
+
+	seconds := 0
+	if call.Args != "off" {
+		var err error
+		seconds, err = strconv.Atoi(call.Args)
+		if err != nil || seconds < 0 || seconds > int(maxSlowMode.Seconds()) {
+			return command.Result{}, command.Errorf("Interval must be between 0 and %d seconds", int(maxSlowMode.Seconds()))
+		}
+	}
+
+	h.slowMode.SetInterval(time.Duration(seconds) * time.Second)
+	h.broadcast("slow_mode", domain.SlowModeState{Seconds: seconds})
+	if seconds == 0 {
+		return command.Result{Reply: "Slow mode is off"}, nil
+	}
+	return command.Result{Reply: fmt.Sprintf("Slow mode: one message per %d seconds", seconds)}, nil
+}
+
+func (h *Handler) lockCommand(ctx context.Context, call command.Call) (command.Result, error) {
+	var readOnly bool
+	switch call.Args {
+	case "":
+		readOnly = !h.Hub.ReadOnly()
+	case "on":
+		readOnly = true
+	case "off":
+		readOnly = false
+	default:
+		return command.Result{}, command.ErrUsage
+	}
+
+	if err := h.setReadOnly(readOnly); err != nil {
+		return command.Result{}, err
+	}
+	if readOnly {
+		return command.Result{Reply: "Chat is read-only"}, nil
+	}
+	return command.Result{Reply: "Chat is open"}, nil
+}
@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// deletes all messages matching the form filters.
+// With dry_run set only the number of matching messages is returned
+func (h *Handler) BulkDelete(w http.ResponseWriter, r *http.Request) {
+	filter, err := usecase.ParseMessageFilter(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+
+	if r.FormValue("dry_run") != "" {
+		count, err := postgres.CountMessages(h.DBPool, filter)
+		if errors.Is(err, domain.ErrInvalidPattern) {
+			render.Error(w, r, err, http.StatusBadRequest, "Invalid pattern, postgres can't compile this regex")
+			return
+		}
+		if err != nil {
+			render.Error(w, r, err, http.StatusInternalServerError, "Failed to count messages")
+			return
+		}
+		fmt.Fprintf(w, "%d messages would be deleted", count)
+		return
+	}
+
+	ids, err := h.deleteMessages(filter)
+	if errors.Is(err, domain.ErrInvalidPattern) {
+		render.Error(w, r, err, http.StatusBadRequest, "Invalid pattern, postgres can't compile this regex")
+		return
+	}
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to delete messages")
+		return
+	}
+	fmt.Fprintf(w, "%d messages deleted", len(ids))
+}
+
+func (h *Handler) deleteMessages(filter domain.MessageFilter) ([]int64, error) {
+	ids, err := postgres.DeleteMessages(h.DBPool, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > 0 {
+		// one event for the whole batch, so clients update in a single pass
+		h.broadcast("delete_messages", domain.BulkDeleteResult{IDs: ids})
+		go usecase.CleanupAttachments(h.logContext(), h.DBPool, h.Blobs)
+	}
+	h.afterDelete(ids)
+	return ids, nil
+}
@@ -3,29 +3,23 @@ package v1
 import (
 	"net/http"
 
-	"github.com/acakp/dumbchat/internal/adapter/postgres"
 	"github.com/acakp/dumbchat/internal/usecase"
 	"github.com/acakp/dumbchat/pkg/render"
 )
 
 func (h *Handler) Chat(w http.ResponseWriter, r *http.Request) {
-	c, err := r.Cookie("admin_session")
-	isAdmin := false
-	if err == nil {
-		if erra := postgres.IsAdminSession(h.DBPool, c); erra == nil {
-			isAdmin = true
-		}
-	}
+	viewer := usecase.GetViewer(h.DBPool, r, h.Cfg.SiteSecret)
 
-	chatView, err := usecase.GetChatView(h.DBPool, isAdmin, h.URLs)
+	chatView, err := usecase.GetChatView(h.DBPool, viewer, h.URLs, h.Cfg)
 	if err != nil {
-		render.Error(w, err, http.StatusInternalServerError, "Failed to load chat")
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load chat")
 		return
 	}
+	chatView.ReadOnly = h.Hub.ReadOnly()
 
 	err = h.Tmpls.ChatTmpl.Execute(w, chatView)
 	if err != nil {
-		render.Error(w, err, http.StatusInternalServerError, "Failed to load chat template")
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load chat template")
 		return
 	}
 }
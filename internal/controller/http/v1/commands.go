@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/controller/ws"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/command"
+)
+
+// runs the slash command the message starts with. The returned message
+// is what is left to post: a command that posts nothing returns empty
+// content and a reply. Other lines come back unchanged, but for "//"
+// losing its escaping slash. Errors are *requestError
+func (h *Handler) runCommand(r *http.Request, msg domain.Message) (domain.Message, *domain.CommandReply, error) {
+	name, args, ok := command.Parse(msg.Content)
+	if !ok {
+		msg.Content = command.Unescape(msg.Content)
+		return msg, nil, nil
+	}
+
+	banned, err := postgres.IsBanned(h.DBPool, msg.IP)
+	if err != nil {
+		return msg, nil, fail(err, http.StatusInternalServerError, "Failed to run command")
+	}
+	if banned {
+		return msg, nil, fail(domain.ErrBanned, http.StatusForbidden, "You are banned from this chat")
+	}
+
+	res, err := h.Commands.Run(r.Context(), command.Call{
+		Name:     name,
+		Args:     args,
+		Nickname: msg.Nickname,
+		IP:       msg.IP,
+		IsAdmin:  usecase.IsAdmin(h.DBPool, r),
+		Request:  r,
+	})
+	reply := &domain.CommandReply{Command: name, Text: res.Reply, Nickname: res.Nickname}
+	if err != nil {
+		reply.Text = h.commandErrorText(name, err)
+		res = command.Result{}
+	}
+
+	msg.Content = res.Post
+	if res.Action {
+		msg.Kind = domain.MessageKindAction
+	}
+	h.sendCommandReply(r, *reply)
+	return msg, reply, nil
+}
+
+func (h *Handler) commandErrorText(name string, err error) string {
+	var userErr *command.Error
+	switch {
+	case errors.Is(err, command.ErrUnknown):
+		return fmt.Sprintf("Unknown command /%s, see /help. Start the message with // to post it as is", name)
+	case errors.Is(err, command.ErrForbidden):
+		return fmt.Sprintf("/%s is restricted to admins", name)
+	case errors.Is(err, command.ErrUsage):
+		c, _ := h.Commands.Lookup(name)
+		return "Usage: " + c.Help()
+	case errors.As(err, &userErr):
+		return userErr.Message
+	default:
+		h.Log.Error().Err(err).Str("command", name).Msg("Command failed")
+		return fmt.Sprintf("/%s failed, try again later", name)
+	}
+}
+
+// sends the reply to the issuer's websocket, if the widget is connected
+func (h *Handler) sendCommandReply(r *http.Request, reply domain.CommandReply) {
+	if reply.Text == "" && reply.Nickname == "" {
+		return
+	}
+	event := ws.Event{
+		Type: "command_reply",
+		Data: reply,
+	}
+	jsonData, _ := json.Marshal(event)
+	h.Hub.SendToClient(usecase.ClientSocket(r), jsonData)
+}
+
+// renders the reply into the chat form response,
+// for widgets without a websocket connection
+func renderCommandReply(w http.ResponseWriter, reply domain.CommandReply) {
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprintf(w, `<div class="command-reply" data-nickname="%s">%s</div>`,
+		template.HTMLEscapeString(reply.Nickname), template.HTMLEscapeString(reply.Text))
+}
@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/markdown"
+)
+
+// runs all checks on a parsed message, saves it with the attached file
+// and notifies the clients. Errors are *requestError
+func (h *Handler) createMessage(w http.ResponseWriter, r *http.Request, msg domain.Message) (domain.Message, error) {
+	banned, err := postgres.IsBanned(h.DBPool, msg.IP)
+	if err != nil {
+		return msg, fail(err, http.StatusInternalServerError, "Failed to save message")
+	}
+	if banned {
+		return msg, fail(domain.ErrBanned, http.StatusForbidden, "You are banned from this chat")
+	}
+	if err = h.beforeMessage(r, &msg); err != nil {
+		return msg, err
+	}
+
+	if err = usecase.ValidateContent(msg.Content, h.Cfg.MaxMessageLength); err != nil {
+		return msg, fail(err, http.StatusBadRequest, err.Error())
+	}
+	if err = usecase.ValidateNicknameFormat(msg.Nickname, h.Cfg.MaxNicknameLength); err != nil {
+		return msg, fail(err, http.StatusBadRequest, err.Error())
+	}
+
+	isAdmin := usecase.IsAdmin(h.DBPool, r)
+	if h.Hub.ReadOnly() && !isAdmin {
+		return msg, fail(domain.ErrReadOnly, http.StatusForbidden, "Chat is in read-only mode, try again later")
+	}
+
+	// check nickname for banned words (e.g. "admin")
+	if isAdmin == false {
+		if err = usecase.ValidateNickname(msg, h.Cfg.BannedNicknames); err != nil {
+			return msg, fail(err, http.StatusBadRequest, "Nickname contains prohibited words")
+		}
+	}
+
+	// registered nicknames can only be used by their owners
+	msg.Verified, err = usecase.CheckNicknameOwner(h.DBPool, r, msg.Nickname, h.Cfg.SiteSecret)
+	if err != nil {
+		if errors.Is(err, domain.ErrNicknameReserved) {
+			return msg, fail(err, http.StatusForbidden, "This nickname is registered, sign in with its passphrase to use it")
+		}
+		return msg, fail(err, http.StatusInternalServerError, "Failed to save message")
+	}
+
+	if msg.ReplyTo != nil {
+		parent, err := postgres.GetMessage(h.DBPool, int(*msg.ReplyTo))
+		if err == nil && parent.Hidden {
+			err = domain.ErrMessageNotFound
+		}
+		if err != nil {
+			return msg, fail(err, http.StatusBadRequest, "The message you are replying to doesn't exist")
+		}
+	}
+
+	upload, err := usecase.ExtractUpload(r, h.Cfg)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrFileTooLarge):
+			return msg, fail(err, http.StatusRequestEntityTooLarge, "The attached file is too large")
+		case errors.Is(err, domain.ErrUnsupportedFileType):
+			return msg, fail(err, http.StatusUnsupportedMediaType, "This file type is not allowed")
+		default:
+			return msg, fail(err, http.StatusInternalServerError, "Failed to save message")
+		}
+	}
+
+	// the last check, so rejected posts don't start the cooldown
+	if !isAdmin {
+		if ok, wait := h.slowMode.Allow(msg.IP); !ok {
+			return msg, fail(domain.ErrSlowMode, http.StatusTooManyRequests,
+				fmt.Sprintf("Slow mode is on, you can post again in %d seconds", int(math.Ceil(wait.Seconds()))))
+		}
+	}
+
+	authorToken := usecase.IssueAuthorToken(w, r, h.Cfg.SiteSecret)
+	msg.AuthorHash = usecase.AuthorHash(authorToken)
+	msg.ID, err = postgres.InsertMessage(h.DBPool, msg)
+	if err != nil {
+		h.slowMode.Release(msg.IP)
+		return msg, fail(err, http.StatusInternalServerError, "Failed to save message")
+	}
+	if upload != nil {
+		if _, err = usecase.StoreAttachment(h.DBPool, h.Blobs, msg.ID, *upload); err != nil {
+			// don't leave a message without the file it was posted with
+			postgres.DeleteMessage(h.DBPool, int(msg.ID))
+			h.slowMode.Release(msg.IP)
+			return msg, fail(err, http.StatusInternalServerError, "Failed to save the attached file")
+		}
+	}
+
+	// the poster may use the nickname, so its widget gets mentions of it
+	h.Hub.SetClientNickname(usecase.ClientSocket(r), msg.Nickname)
+	h.announceMessage(msg)
+	return msg, nil
+}
+
+// tells clients, webhooks and mentioned visitors about a saved message
+func (h *Handler) announceMessage(msg domain.Message) {
+	// notify websocket hub about new message
+	h.broadcast("new_message", msg)
+	h.unfurlLinks(msg)
+
+	mentioned := markdown.Mentions(msg.Content)
+	if len(mentioned) > 0 {
+		if err := postgres.InsertMentions(h.DBPool, msg.ID, mentioned); err != nil {
+			h.Log.Error().Err(err).Int64("message", msg.ID).Msg("Failed to save mentions")
+		}
+		h.notify(mentioned, "mention", domain.MentionEvent{
+			ID:        msg.ID,
+			Nickname:  msg.Nickname,
+			Mentioned: mentioned,
+		})
+	}
+	if h.Hooks.AfterMessage != nil {
+		h.Hooks.AfterMessage(msg)
+	}
+}
+
+// reads a message body of the chat form size plus an attached file
+func (h *Handler) limitMessageBody(w http.ResponseWriter, r *http.Request) {
+	// leave some room for the other form fields
+	r.Body = http.MaxBytesReader(w, r.Body, max(h.Cfg.MaxUploadBytes, 0)+1<<20)
+}
+
+// maps body parsing errors to responses
+func parseError(err error) *requestError {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return fail(err, http.StatusRequestEntityTooLarge, "The attached file is too large")
+	}
+	return fail(err, http.StatusBadRequest, "Error parsing form, content field may be empty")
+}
@@ -1,39 +1,42 @@
 package v1
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 
 	"github.com/acakp/dumbchat/internal/adapter/postgres"
-	"github.com/acakp/dumbchat/internal/controller/ws"
 	"github.com/acakp/dumbchat/internal/domain"
 	"github.com/acakp/dumbchat/internal/usecase"
-	"github.com/acakp/dumbchat/pkg/render"
 )
 
 func (h *Handler) DeleteMessage(w http.ResponseWriter, r *http.Request) {
 	messageID, err := usecase.ExtractMessageID(r)
 	if err != nil {
-		render.Error(w, err, http.StatusBadRequest, "Bad request")
+		renderError(w, r, fail(err, http.StatusBadRequest, "Bad request"))
 		return
 	}
+	if err = h.deleteMessage(messageID); err != nil {
+		renderError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// deletes a message as a moderator. Errors are *requestError
+func (h *Handler) deleteMessage(messageID int) error {
 	msg, err := postgres.GetMessage(h.DBPool, messageID)
-	err = postgres.DeleteMessage(h.DBPool, messageID)
+	if err == nil {
+		err = postgres.DeleteMessage(h.DBPool, messageID)
+	}
 	if err != nil {
 		if errors.Is(err, domain.ErrMessageNotFound) {
-			render.Error(w, err, http.StatusNotFound, "Message not found")
-		} else {
-			render.Error(w, err, http.StatusInternalServerError, "Internal Server Error")
+			return fail(err, http.StatusNotFound, "Message not found")
 		}
-		return
+		return fail(err, http.StatusInternalServerError, "Internal Server Error")
 	}
 	// notify websocket hub about deleting a  message
-	event := ws.Event{
-		Type: "delete_message",
-		Data: msg,
-	}
-	jsonData, _ := json.Marshal(event)
-	h.Hub.Broadcast <- jsonData
-	w.WriteHeader(http.StatusOK)
+	h.broadcast("delete_message", msg)
+	go usecase.CleanupAttachments(h.logContext(), h.DBPool, h.Blobs)
+	h.afterDelete([]int64{msg.ID})
+	return nil
 }
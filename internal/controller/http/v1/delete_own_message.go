@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// lets visitors delete messages they posted,
+// authorship is proven by the author token cookie
+func (h *Handler) DeleteOwnMessage(w http.ResponseWriter, r *http.Request) {
+	messageID, err := usecase.ExtractMessageID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	msg, err := postgres.GetMessage(h.DBPool, messageID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Message not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+
+	viewer := usecase.GetViewer(h.DBPool, r, h.Cfg.SiteSecret)
+	if !viewer.IsAuthorOf(msg) {
+		render.Error(w, r, errors.New("not the author"), http.StatusForbidden, "You can only delete your own messages")
+		return
+	}
+	if err = h.checkCanWrite(r, viewer.IsAdmin); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	err = postgres.DeleteMessage(h.DBPool, messageID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Message not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+
+	h.broadcast("delete_message", msg)
+	go usecase.CleanupAttachments(h.logContext(), h.DBPool, h.Blobs)
+	h.afterDelete([]int64{msg.ID})
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// clears all reports of a message and makes it visible again
+func (h *Handler) DismissReports(w http.ResponseWriter, r *http.Request) {
+	messageID, err := usecase.ExtractMessageID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	if err = postgres.DeleteReports(h.DBPool, int64(messageID)); err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	msg, err := postgres.GetMessage(h.DBPool, messageID)
+	if err != nil {
+		// the message may have been removed in the meantime, nothing to unhide then
+		if !errors.Is(err, domain.ErrMessageNotFound) {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+			return
+		}
+	} else if msg.Hidden {
+		if err = postgres.SetMessageHidden(h.DBPool, msg.ID, false); err != nil {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+			return
+		}
+		// visitors load it back, admins drop the hidden mark
+		msg.Hidden = false
+		h.broadcast("unhide_message", msg)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,179 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+	"github.com/acakp/dumbchat/pkg/textutil"
+)
+
+// renders the inline edit form in place of the message
+func (h *Handler) EditMessageForm(w http.ResponseWriter, r *http.Request) {
+	messageID, err := usecase.ExtractMessageID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	msg, err := postgres.GetMessage(h.DBPool, messageID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Message not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+
+	viewer := usecase.GetViewer(h.DBPool, r, h.Cfg.SiteSecret)
+	if msg.Hidden && !viewer.IsAdmin {
+		render.Error(w, r, domain.ErrMessageNotFound, http.StatusNotFound, "Message not found")
+		return
+	}
+	msv := usecase.NewMessageView(msg, viewer, h.URLs, h.Cfg)
+	if !msv.CanEdit {
+		render.Error(w, r, errors.New("edit not allowed"), http.StatusForbidden, "You can't edit this message")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	h.Tmpls.MessageTmpl.ExecuteTemplate(w, "edit", msv)
+}
+
+func (h *Handler) EditMessage(w http.ResponseWriter, r *http.Request) {
+	messageID, err := usecase.ExtractMessageID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+	err = r.ParseForm()
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Error parsing form")
+		return
+	}
+	content := textutil.Clean(r.FormValue("content"), true)
+	if content == "" {
+		render.Error(w, r, errors.New("empty content"), http.StatusBadRequest, "Content field is required")
+		return
+	}
+
+	msg, err := postgres.GetMessage(h.DBPool, messageID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Message not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+
+	viewer := usecase.GetViewer(h.DBPool, r, h.Cfg.SiteSecret)
+	if msg.Hidden && !viewer.IsAdmin {
+		render.Error(w, r, domain.ErrMessageNotFound, http.StatusNotFound, "Message not found")
+		return
+	}
+	if !usecase.CanEditMessage(msg, viewer, h.Cfg.EditWindow) {
+		render.Error(w, r, errors.New("edit not allowed"), http.StatusForbidden, "You can't edit this message")
+		return
+	}
+	if err = h.checkCanWrite(r, viewer.IsAdmin); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	// the hook sees the edited message, only its content is saved
+	edited := msg
+	edited.Content = content
+	if err = h.beforeMessage(r, &edited); err != nil {
+		renderError(w, r, err)
+		return
+	}
+	if err = usecase.ValidateContent(edited.Content, h.Cfg.MaxMessageLength); err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	edit := domain.MessageEdit{
+		MessageID: msg.ID,
+		EditedAt:  time.Now(),
+		EditedBy:  domain.EditorAuthor,
+	}
+	if viewer.IsAdmin {
+		edit.EditedBy = domain.EditorAdmin
+	}
+	if edited.Content != msg.Content {
+		err = postgres.UpdateMessageContent(h.DBPool, edit, edited.Content)
+		if err != nil {
+			render.Error(w, r, err, http.StatusInternalServerError, "Failed to save message")
+			return
+		}
+		msg.Content = edited.Content
+		msg.EditedAt = &edit.EditedAt
+		// an admin editing a hidden message keeps it to themselves
+		if !msg.Hidden {
+			h.announceEdit(msg)
+		}
+	}
+
+	views := []domain.MessageView{usecase.NewMessageView(msg, viewer, h.URLs, h.Cfg)}
+	if err = usecase.AttachMessageDetails(h.DBPool, views, viewer.ReactorID); err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	err = h.Tmpls.MessageTmpl.ExecuteTemplate(w, "msg", views[0])
+	if err != nil {
+		err = fmt.Errorf("Error rendering message (Handler.EditMessage): %w", err)
+		render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+	}
+}
+
+// renders previous versions of a message
+func (h *Handler) MessageEdits(w http.ResponseWriter, r *http.Request) {
+	messageID, err := usecase.ExtractMessageID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	msg, err := postgres.GetMessage(h.DBPool, messageID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Message not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+	// the history of hidden messages is as private as their content
+	viewer := usecase.GetViewer(h.DBPool, r, h.Cfg.SiteSecret)
+	if msg.Hidden && !viewer.IsAdmin {
+		render.Error(w, r, domain.ErrMessageNotFound, http.StatusNotFound, "Message not found")
+		return
+	}
+
+	edits, err := postgres.GetMessageEdits(h.DBPool, msg.ID)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	h.Tmpls.MessageTmpl.ExecuteTemplate(w, "edits", edits)
+}
+
+// clients swap the message line in place
+func (h *Handler) announceEdit(msg domain.Message) {
+	line, err := h.renderFragment("message-line", usecase.NewMessageView(msg, domain.Viewer{}, h.URLs, h.Cfg))
+	if err != nil {
+		h.Log.Error().Err(err).Int64("message", msg.ID).Msg("Failed to render edited message")
+	}
+	h.broadcastRendered("edit_message", msg, domain.RenderedEdit{Message: msg, Line: line})
+	h.unfurlLinks(msg)
+}
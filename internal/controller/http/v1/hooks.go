@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/controller/ws"
+	"github.com/acakp/dumbchat/internal/domain"
+)
+
+// Hooks let host applications check and follow chat activity,
+// nil funcs are skipped. Websocket connections have their hooks on ws.Hub
+type Hooks struct {
+	// runs before a message is saved, after its slash command, before the
+	// content checks. It may change msg, an error rejects the message and
+	// its text is shown to the poster. Edits run it too, with msg.ID set;
+	// only the content of an edited message is saved
+	BeforeMessage func(r *http.Request, msg *domain.Message) error
+	// runs after a message is saved and sent to the clients
+	AfterMessage func(msg domain.Message)
+	// runs after messages are deleted by admins or their authors
+	OnDelete func(ids []int64)
+}
+
+func (h *Handler) beforeMessage(r *http.Request, msg *domain.Message) error {
+	if h.Hooks.BeforeMessage == nil {
+		return nil
+	}
+	if err := h.Hooks.BeforeMessage(r, msg); err != nil {
+		return fail(err, http.StatusForbidden, err.Error())
+	}
+	return nil
+}
+
+func (h *Handler) afterDelete(ids []int64) {
+	if h.Hooks.OnDelete != nil && len(ids) > 0 {
+		h.Hooks.OnDelete(ids)
+	}
+}
+
+// Subscribe calls fn with every event sent to all clients, and with
+// mention events. fn runs on the goroutine that caused the event, so it
+// must return quickly and must not call Subscribe or the unsubscribe func
+func (h *Handler) Subscribe(fn func(ws.Event)) (unsubscribe func()) {
+	h.subMu.Lock()
+	defer h.subMu.Unlock()
+	h.nextSub++
+	id := h.nextSub
+	h.subscribers[id] = fn
+	return func() {
+		h.subMu.Lock()
+		defer h.subMu.Unlock()
+		delete(h.subscribers, id)
+	}
+}
+
+// passes the event to the subscribers. Unsubscribing waits for
+// running calls, so a subscriber isn't called after it returns
+func (h *Handler) publish(event ws.Event) {
+	h.subMu.RLock()
+	defer h.subMu.RUnlock()
+	for _, fn := range h.subscribers {
+		fn(event)
+	}
+}
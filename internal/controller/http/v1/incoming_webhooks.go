@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+	"github.com/go-chi/chi/v5"
+)
+
+// limit of the JSON body, text is checked against MaxMessageLength later
+const maxHookBodyBytes = 64 << 10
+
+// posts a bot message sent to the secret URL of an incoming webhook.
+// Hooks are set up by admins, so they may post in read-only mode too
+func (h *Handler) PostIncomingWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, err := postgres.UseIncomingWebhook(h.DBPool, usecase.AuthorHash(chi.URLParam(r, "token")))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			render.JSONError(w, r, err, http.StatusNotFound, "Unknown webhook")
+		} else {
+			render.JSONError(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+	if !h.hookLimiter.Allow(strconv.FormatInt(hook.ID, 10)) {
+		render.JSONError(w, r, errors.New("incoming webhook rate limit"), http.StatusTooManyRequests, "Rate limit exceeded")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxHookBodyBytes)
+	msg, err := usecase.ParseIncomingWebhookPayload(r, hook)
+	if err != nil {
+		render.JSONError(w, r, err, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err = h.beforeMessage(r, &msg); err != nil {
+		renderJSONError(w, r, err)
+		return
+	}
+	if err = usecase.ValidateContent(msg.Content, h.Cfg.MaxMessageLength); err != nil {
+		render.JSONError(w, r, err, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err = usecase.ValidateNicknameFormat(msg.Nickname, h.Cfg.MaxNicknameLength); err != nil {
+		render.JSONError(w, r, err, http.StatusBadRequest, err.Error())
+		return
+	}
+	// a name from the payload is checked like a visitor's one,
+	// the hook's own name was chosen by an admin
+	if msg.Nickname != hook.Name {
+		if err = usecase.ValidateNickname(msg, h.Cfg.BannedNicknames); err != nil {
+			render.JSONError(w, r, err, http.StatusBadRequest, "Nickname contains prohibited words")
+			return
+		}
+		if _, err = usecase.CheckNicknameOwner(h.DBPool, r, msg.Nickname, h.Cfg.SiteSecret); err != nil {
+			if errors.Is(err, domain.ErrNicknameReserved) {
+				render.JSONError(w, r, err, http.StatusForbidden, "This nickname is registered, bots can't post under it")
+			} else {
+				render.JSONError(w, r, err, http.StatusInternalServerError, "Failed to save message")
+			}
+			return
+		}
+	}
+
+	msg.ID, err = postgres.InsertMessage(h.DBPool, msg)
+	if err != nil {
+		render.JSONError(w, r, err, http.StatusInternalServerError, "Failed to save message")
+		return
+	}
+	h.announceMessage(msg)
+	h.renderAPIMessage(w, r, http.StatusCreated, msg, domain.Viewer{})
+}
+
+// renders the hook URL once, it can't be shown again later
+func (h *Handler) CreateIncomingWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, err := usecase.ParseIncomingWebhook(r, h.Cfg.MaxNicknameLength)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	hook, token, err := usecase.CreateIncomingWebhook(h.DBPool, hook)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to create webhook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	view := domain.IncomingWebhookView{Hook: hook, URL: h.URLs.IncomingHook(token), URLs: h.URLs}
+	err = h.Tmpls.AdminTmpl.ExecuteTemplate(w, "incoming-hook-created", view)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load admin template")
+		return
+	}
+}
+
+func (h *Handler) DeleteIncomingWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := usecase.ExtractIncomingWebhookID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	err = postgres.DeleteIncomingWebhook(h.DBPool, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Webhook not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
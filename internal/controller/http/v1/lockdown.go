@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// settings key of the lockdown mode
+const readOnlySetting = "read_only"
+
+// switches read-only mode on or off and notifies connected clients
+func (h *Handler) Lockdown(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Error parsing form")
+		return
+	}
+	readOnly, err := strconv.ParseBool(r.FormValue("read_only"))
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	if err = h.setReadOnly(readOnly); err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to save lockdown mode")
+		return
+	}
+	http.Redirect(w, r, h.URLs.Admin, http.StatusSeeOther)
+}
+
+// saves the mode, so it survives restarts
+func (h *Handler) setReadOnly(readOnly bool) error {
+	if err := postgres.SetSetting(h.DBPool, readOnlySetting, strconv.FormatBool(readOnly)); err != nil {
+		return err
+	}
+	h.Hub.SetReadOnly(readOnly)
+	h.broadcast("lockdown", domain.LockdownState{ReadOnly: readOnly})
+	return nil
+}
+
+// applies the lockdown mode saved before a restart.
+// CHAT_READ_ONLY locks the chat at start regardless of the saved mode
+func (h *Handler) restoreReadOnly() error {
+	value, ok, err := postgres.GetSetting(h.DBPool, readOnlySetting)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	readOnly, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid saved lockdown mode %q: %w", value, err)
+	}
+	h.Hub.SetReadOnly(readOnly || h.Cfg.ReadOnly)
+	return nil
+}
+
+// rejects changes by banned visitors, and by all visitors
+// while the chat is read-only. Errors are *requestError
+func (h *Handler) checkCanWrite(r *http.Request, isAdmin bool) error {
+	banned, err := postgres.IsBanned(h.DBPool, usecase.ClientIP(r))
+	if err != nil {
+		return fail(err, http.StatusInternalServerError, "Internal Server Error")
+	}
+	if banned {
+		return fail(domain.ErrBanned, http.StatusForbidden, "You are banned from this chat")
+	}
+	if h.Hub.ReadOnly() && !isAdmin {
+		return fail(domain.ErrReadOnly, http.StatusForbidden, "Chat is in read-only mode, try again later")
+	}
+	return nil
+}
@@ -1,13 +1,9 @@
 package v1
 
 import (
-	"encoding/json"
 	"net/http"
 
-	"github.com/acakp/dumbchat/internal/adapter/postgres"
-	"github.com/acakp/dumbchat/internal/controller/ws"
 	"github.com/acakp/dumbchat/internal/usecase"
-	"github.com/acakp/dumbchat/pkg/render"
 )
 
 func (h *Handler) Messages(w http.ResponseWriter, r *http.Request) {
@@ -16,40 +12,28 @@ func (h *Handler) Messages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	msg, err := usecase.ParseMessage(r)
+	h.limitMessageBody(w, r)
+	msg, err := usecase.ParseMessage(r, h.Cfg.SiteSecret)
 	if err != nil {
-		render.Error(w, err, http.StatusBadRequest, "Error parsing form, content field may be empty")
+		renderError(w, r, parseError(err))
 		return
 	}
 
-	// check nickname for banned words (e.g. "admin")
-	c, err := r.Cookie("admin_session")
-	isAdmin := false
-	if err == nil {
-		if err = postgres.IsAdminSession(h.DBPool, c); err == nil {
-			isAdmin = true
-		}
+	msg, reply, err := h.runCommand(r, msg)
+	if err != nil {
+		renderError(w, r, err)
+		return
 	}
-	if isAdmin == false {
-		if err = usecase.ValidateNickname(msg, h.Cfg.BannedNicknames); err != nil {
-			render.Error(w, err, http.StatusBadRequest, "Nickname contains prohibited words")
-			return
+	if reply != nil && msg.Content == "" {
+		// without a websocket the reply is appended to the chat by the form
+		if usecase.ClientSocket(r) == "" {
+			renderCommandReply(w, *reply)
 		}
-	}
-
-	// process the form data
-	msg.TruncateMessageContent()
-	msg.ID, err = postgres.InsertMessage(h.DBPool, msg)
-	if err != nil {
-		render.Error(w, err, http.StatusInternalServerError, "Failed to save message")
 		return
 	}
 
-	// notify websocket hub about new message
-	event := ws.Event{
-		Type: "new_message",
-		Data: msg,
+	if _, err = h.createMessage(w, r, msg); err != nil {
+		renderError(w, r, err)
+		return
 	}
-	jsonData, _ := json.Marshal(event)
-	h.Hub.Broadcast <- jsonData
 }
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// registers a free nickname to the visitor
+// or signs them in to a nickname they registered before
+func (h *Handler) ClaimNickname(w http.ResponseWriter, r *http.Request) {
+	if !h.claimLimiter.Allow(usecase.ClientIP(r)) {
+		render.Error(w, r, errors.New("claim rate limit"), http.StatusTooManyRequests, "Too many attempts, try again later")
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Error parsing form")
+		return
+	}
+
+	nickname := usecase.NormalizeNickname(r.FormValue("nickname"))
+	passphrase := r.FormValue("passphrase")
+	if nickname == "" || strings.EqualFold(nickname, "anonymous") {
+		render.Error(w, r, errors.New("empty nickname"), http.StatusBadRequest, "Choose a nickname first")
+		return
+	}
+	if err = usecase.ValidateNicknameFormat(nickname, h.Cfg.MaxNicknameLength); err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !usecase.IsAdmin(h.DBPool, r) {
+		if err = usecase.ValidateNickname(domain.Message{Nickname: nickname}, h.Cfg.BannedNicknames); err != nil {
+			render.Error(w, r, err, http.StatusBadRequest, "Nickname contains prohibited words")
+			return
+		}
+	}
+
+	identityHash := usecase.IssueIdentityToken(w, r, h.Cfg.SiteSecret)
+	registered, err := usecase.ClaimNickname(h.DBPool, nickname, passphrase, identityHash)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPassphraseTooShort):
+			render.Error(w, r, err, http.StatusBadRequest,
+				fmt.Sprintf("Passphrase must be at least %d characters long", usecase.MinPassphraseLength))
+		case errors.Is(err, domain.ErrWrongPassphrase):
+			render.Error(w, r, err, http.StatusForbidden, "Wrong passphrase for this nickname")
+		case errors.Is(err, domain.ErrNicknameReserved):
+			render.Error(w, r, err, http.StatusConflict, "This nickname has just been registered by someone else")
+		default:
+			render.Error(w, r, err, http.StatusInternalServerError, "Failed to register nickname")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	if registered {
+		fmt.Fprintf(w, "%s is now registered to you", html.EscapeString(nickname))
+	} else {
+		fmt.Fprintf(w, "signed in as %s", html.EscapeString(nickname))
+	}
+}
+
+// signs the browser out of its registered nickname
+func (h *Handler) SignOutNickname(w http.ResponseWriter, r *http.Request) {
+	if hash := usecase.IdentityHash(r, h.Cfg.SiteSecret); hash != "" {
+		if err := postgres.DeleteNicknameSession(h.DBPool, hash); err != nil {
+			render.Error(w, r, err, http.StatusInternalServerError, "Failed to sign out")
+			return
+		}
+	}
+	usecase.ClearIdentityToken(w)
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprint(w, "signed out")
+}
@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// renders the full chat page centred on a single message
+func (h *Handler) Permalink(w http.ResponseWriter, r *http.Request) {
+	messageID, err := usecase.ExtractMessageID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	viewer := usecase.GetViewer(h.DBPool, r, h.Cfg.SiteSecret)
+	chatView, err := usecase.GetContextView(h.DBPool, messageID, viewer, h.URLs, h.Cfg)
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Message not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Failed to load chat")
+		}
+		return
+	}
+	chatView.ReadOnly = h.Hub.ReadOnly()
+
+	err = h.Tmpls.ChatTmpl.Execute(w, chatView)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load chat template")
+		return
+	}
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+func (h *Handler) PinMessage(w http.ResponseWriter, r *http.Request) {
+	h.setMessagePinned(w, r, true)
+}
+
+func (h *Handler) UnpinMessage(w http.ResponseWriter, r *http.Request) {
+	h.setMessagePinned(w, r, false)
+}
+
+func (h *Handler) setMessagePinned(w http.ResponseWriter, r *http.Request, pinned bool) {
+	messageID, err := usecase.ExtractMessageID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	err = postgres.SetMessagePinned(h.DBPool, int64(messageID), pinned)
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Message not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+
+	eventType := "unpin"
+	if pinned {
+		eventType = "pin"
+	}
+	h.broadcast(eventType, domain.PinEvent{Kind: domain.PinKindMessage, ID: int64(messageID)})
+	w.WriteHeader(http.StatusOK)
+}
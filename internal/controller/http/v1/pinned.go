@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// renders the sticky area with pinned messages and announcements
+func (h *Handler) Pinned(w http.ResponseWriter, r *http.Request) {
+	isAdmin := usecase.IsAdmin(h.DBPool, r)
+
+	view, err := usecase.GetPinnedView(h.DBPool, isAdmin, h.URLs)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load pinned messages")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = h.Tmpls.PinnedTmpl.ExecuteTemplate(w, "pinned", view)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load pinned template")
+		return
+	}
+}
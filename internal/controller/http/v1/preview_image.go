@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/pkg/render"
+	"github.com/go-chi/chi/v5"
+)
+
+// serves a downloaded link preview image
+func (h *Handler) PreviewImage(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	// attachments share the blob store, they're served with their own checks
+	if !strings.HasPrefix(key, "preview-") {
+		render.Error(w, r, domain.ErrNotFound, http.StatusNotFound, "File not found")
+		return
+	}
+
+	blob, err := h.Blobs.Open(key)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "File not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+	defer blob.Close()
+
+	w.Header().Set("Content-Type", mime.TypeByExtension(path.Ext(key)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	// the image is replaced when the preview is refetched
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	io.Copy(w, blob)
+}
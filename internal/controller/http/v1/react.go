@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// toggles the visitor's reaction on a message
+func (h *Handler) React(w http.ResponseWriter, r *http.Request) {
+	messageID, err := usecase.ExtractMessageID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+	err = r.ParseForm()
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Error parsing form")
+		return
+	}
+	emoji := r.FormValue("emoji")
+	if !domain.IsReactionEmoji(emoji) {
+		render.Error(w, r, errors.New("unknown emoji"), http.StatusBadRequest, "Unsupported reaction")
+		return
+	}
+
+	viewer := usecase.GetViewer(h.DBPool, r, h.Cfg.SiteSecret)
+	if err = h.checkCanWrite(r, viewer.IsAdmin); err != nil {
+		renderError(w, r, err)
+		return
+	}
+	if !h.reactionLimiter.Allow(viewer.ReactorID) {
+		render.Error(w, r, errors.New("reaction rate limit"), http.StatusTooManyRequests, "Too many reactions, slow down")
+		return
+	}
+
+	msg, err := postgres.GetMessage(h.DBPool, messageID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Message not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+	if msg.Hidden && !viewer.IsAdmin {
+		render.Error(w, r, domain.ErrMessageNotFound, http.StatusNotFound, "Message not found")
+		return
+	}
+
+	reaction := domain.Reaction{
+		MessageID: msg.ID,
+		Emoji:     emoji,
+		Reactor:   viewer.ReactorID,
+		CreatedAt: time.Now(),
+	}
+	if _, err = postgres.ToggleReaction(h.DBPool, reaction); err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to save reaction")
+		return
+	}
+
+	counts, err := postgres.GetReactions(h.DBPool, []int64{msg.ID}, viewer.ReactorID)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+	// clients apply the counts, the reactor also gets its own marked.
+	// Visitors don't learn that a hidden message is still there
+	if !msg.Hidden {
+		h.broadcast("reaction_update", domain.ReactionUpdate{
+			ID:        msg.ID,
+			Reactions: counts[msg.ID],
+		})
+	}
+
+	msv := usecase.NewMessageView(msg, viewer, h.URLs, h.Cfg)
+	msv.Reactions = counts[msg.ID]
+	w.Header().Set("Content-Type", "text/html")
+	err = h.Tmpls.MessageTmpl.ExecuteTemplate(w, "reactions", msv)
+	if err != nil {
+		err = fmt.Errorf("Error rendering reactions (Handler.React): %w", err)
+		render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+	}
+}
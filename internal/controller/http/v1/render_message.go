@@ -14,26 +14,33 @@ import (
 func (h *Handler) RenderMessage(w http.ResponseWriter, r *http.Request) {
 	messageID, err := usecase.ExtractMessageID(r)
 	if err != nil {
-		render.Error(w, err, http.StatusBadRequest, "Bad request")
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
 		return
 	}
 
 	msg, err := postgres.GetMessage(h.DBPool, messageID)
 	if err != nil {
 		if errors.Is(err, domain.ErrMessageNotFound) {
-			render.Error(w, err, http.StatusNotFound, "Message not found")
+			render.Error(w, r, err, http.StatusNotFound, "Message not found")
 		} else {
 			err = fmt.Errorf("Error rendering message (Handler.renderMessage): %w", err)
-			render.Error(w, err, http.StatusInternalServerError, "Internal Server Error")
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
 		}
 		return
 	}
 
-	msv := domain.MessageView{
-		Msg:     msg,
-		IsAdmin: false,
-		URLs:    h.URLs,
+	// fragments are fetched by each client itself,
+	// so admins get moderation controls on live messages too
+	viewer := usecase.GetViewer(h.DBPool, r, h.Cfg.SiteSecret)
+	if msg.Hidden && !viewer.IsAdmin {
+		render.Error(w, r, domain.ErrMessageNotFound, http.StatusNotFound, "Message not found")
+		return
+	}
+	views := []domain.MessageView{usecase.NewMessageView(msg, viewer, h.URLs, h.Cfg)}
+	if err = usecase.AttachMessageDetails(h.DBPool, views, viewer.ReactorID); err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		return
 	}
 	w.Header().Set("Content-Type", "text/html")
-	h.Tmpls.MessageTmpl.ExecuteTemplate(w, "msg", msv)
+	h.Tmpls.MessageTmpl.ExecuteTemplate(w, "msg", views[0])
 }
@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+func (h *Handler) ReportMessage(w http.ResponseWriter, r *http.Request) {
+	messageID, err := usecase.ExtractMessageID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	msg, err := postgres.GetMessage(h.DBPool, messageID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Message not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+
+	report := domain.Report{
+		MessageID:  msg.ID,
+		Reason:     usecase.ExtractReportReason(r),
+		ReporterIP: usecase.ClientIP(r),
+		CreatedAt:  time.Now(),
+	}
+	count, err := postgres.InsertReport(h.DBPool, report)
+	if err != nil && !errors.Is(err, domain.ErrAlreadyReported) {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to save report")
+		return
+	}
+	if err == nil {
+		h.dispatchWebhooks(domain.EventReport, domain.ReportEvent{
+			MessageID: msg.ID,
+			Reason:    report.Reason,
+			Count:     count,
+			CreatedAt: report.CreatedAt,
+		})
+	}
+
+	// hide the message once enough visitors have reported it
+	threshold := h.Cfg.ReportHideThreshold
+	if threshold > 0 && count >= threshold && !msg.Hidden {
+		if err = postgres.SetMessageHidden(h.DBPool, msg.ID, true); err != nil {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+			return
+		}
+		h.broadcast("hide_message", msg)
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprint(w, `<span class="reported">reported</span>`)
+}
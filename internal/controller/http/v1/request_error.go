@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// failed request with the status and message for the client,
+// lets the HTML and JSON handlers share the same checks
+type requestError struct {
+	Status  int
+	Message string
+	Err     error
+}
+
+func (e *requestError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.Err
+}
+
+func fail(err error, status int, message string) *requestError {
+	return &requestError{Status: status, Message: message, Err: err}
+}
+
+// any other error is an internal one
+func asRequestError(err error) *requestError {
+	var re *requestError
+	if errors.As(err, &re) {
+		return re
+	}
+	return fail(err, http.StatusInternalServerError, "Internal Server Error")
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	re := asRequestError(err)
+	render.Error(w, r, re.Err, re.Status, re.Message)
+}
+
+func renderJSONError(w http.ResponseWriter, r *http.Request, err error) {
+	re := asRequestError(err)
+	render.JSONError(w, r, re.Err, re.Status, re.Message)
+}
@@ -4,19 +4,40 @@ import (
 	"net/http"
 
 	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
 	"github.com/acakp/dumbchat/pkg/render"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// requests authenticated by Handler.Authenticate need a token
+// with the moderate scope instead of the admin session
 func RequireAdmin(dbpool *pgxpool.Pool, next http.Handler) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if t, ok := usecase.APITokenFrom(r.Context()); ok {
+			if !t.HasScope(domain.ScopeModerate) {
+				render.Error(w, r, domain.ErrMissingScope, http.StatusForbidden, "Forbidden")
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		RequireAdminSession(dbpool, next)(w, r)
+	})
+}
+
+// same as RequireAdmin, but API tokens aren't accepted,
+// e.g. for managing the tokens themselves
+func RequireAdminSession(dbpool *pgxpool.Pool, next http.Handler) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		cookie, err := r.Cookie("admin_session")
 		if err != nil || cookie.Valid() != nil {
-			render.Error(w, err, http.StatusUnauthorized, "Unauthorized")
+			render.Error(w, r, err, http.StatusUnauthorized, "Unauthorized")
 			return
 		}
 		if err = postgres.IsAdminSession(dbpool, cookie); err != nil {
-			render.Error(w, err, http.StatusUnauthorized, "Unauthorized")
+			render.Error(w, r, err, http.StatusUnauthorized, "Unauthorized")
 			return
 		}
 
@@ -0,0 +1,10 @@
+package v1
+
+// RestoreState loads the state saved before a restart: the lockdown mode
+// and the generated site secret. Call it once the tables exist, before serving
+func (h *Handler) RestoreState() error {
+	if err := h.restoreSiteSecret(); err != nil {
+		return err
+	}
+	return h.restoreReadOnly()
+}
@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"context"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+)
+
+const (
+	// due retries are picked up at least this often
+	webhookPollInterval = 5 * time.Second
+	// finished deliveries are kept in the log this long
+	webhookLogRetention = 30 * 24 * time.Hour
+)
+
+// queues the event for the subscribed webhooks and wakes the worker
+func (h *Handler) dispatchWebhooks(event string, data any) {
+	if !domain.IsWebhookEvent(event) {
+		return
+	}
+	n, err := usecase.EnqueueWebhookEvent(h.DBPool, event, data)
+	if err != nil {
+		h.Log.Error().Err(err).Str("event", event).Msg("Failed to queue webhook deliveries")
+		return
+	}
+	if n > 0 {
+		select {
+		case h.webhookWake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// delivers queued webhook events until ctx is done
+func (h *Handler) RunWebhooks(ctx context.Context) {
+	ctx = h.Log.WithContext(ctx)
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	var lastCleanup time.Time
+
+	for {
+		n, err := usecase.DeliverWebhooks(ctx, h.DBPool, h.webhookSender)
+		if err != nil {
+			h.Log.Error().Err(err).Msg("Failed to deliver webhooks")
+		}
+		if time.Since(lastCleanup) > time.Hour {
+			if err = postgres.DeleteOldWebhookDeliveries(h.DBPool, time.Now().Add(-webhookLogRetention)); err != nil {
+				h.Log.Error().Err(err).Msg("Failed to clean up webhook delivery log")
+			}
+			lastCleanup = time.Now()
+		}
+		// more deliveries may be due already
+		if err == nil && n > 0 && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.webhookWake:
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// renders messages matching the search form
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	q, err := usecase.ParseSearchQuery(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	isAdmin := usecase.IsAdmin(h.DBPool, r)
+	view, err := usecase.SearchMessages(h.DBPool, q, isAdmin, h.URLs)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Search failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	err = h.Tmpls.SearchTmpl.ExecuteTemplate(w, "search-results", view)
+	if err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to load search template")
+		return
+	}
+}
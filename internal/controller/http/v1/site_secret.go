@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+)
+
+// settings key of the secret generated when SITE_SECRET isn't set
+const siteSecretSetting = "site_secret"
+
+// replaces the secret generated by New with the one saved by an earlier start,
+// or saves it, so signed cookies and tripcodes survive restarts
+func (h *Handler) restoreSiteSecret() error {
+	if !h.generatedSecret {
+		return nil
+	}
+	secret, err := postgres.InitSetting(h.DBPool, siteSecretSetting, h.Cfg.SiteSecret)
+	if err != nil {
+		return err
+	}
+	h.Cfg.SiteSecret = secret
+	h.Log.Warn().Msg("SITE_SECRET is not set, using a generated one saved in the database: " +
+		"tripcodes and signed cookies change if it's lost")
+	return nil
+}
@@ -0,0 +1,17 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/rs/zerolog/hlog"
+)
+
+// the nickname of a new websocket connection, see ws.Hub.Identify
+func (h *Handler) socketNickname(r *http.Request) string {
+	nickname, err := usecase.SocketNickname(h.DBPool, r, h.Cfg.SiteSecret)
+	if err != nil {
+		hlog.FromRequest(r).Error().Err(err).Msg("Failed to identify websocket client")
+	}
+	return nickname
+}
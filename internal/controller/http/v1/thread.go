@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+// renders a message with all its replies
+func (h *Handler) Thread(w http.ResponseWriter, r *http.Request) {
+	messageID, err := usecase.ExtractMessageID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	viewer := usecase.GetViewer(h.DBPool, r, h.Cfg.SiteSecret)
+	msgs, err := postgres.GetThread(h.DBPool, int64(messageID), viewer.IsAdmin)
+	if err != nil {
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Message not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+
+	view := domain.ThreadView{URLs: h.URLs}
+	for _, msg := range msgs {
+		view.Messages = append(view.Messages, usecase.NewMessageView(msg, viewer, h.URLs, h.Cfg))
+	}
+	if err = usecase.AttachMessageDetails(h.DBPool, view.Messages, viewer.ReactorID); err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	h.Tmpls.MessageTmpl.ExecuteTemplate(w, "thread", view)
+}
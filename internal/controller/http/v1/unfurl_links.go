@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+)
+
+// fetches a preview of the first link in the message in the background
+// and sends the card to clients once it's ready.
+// The message goes without a preview while all fetch slots are busy
+func (h *Handler) unfurlLinks(msg domain.Message) {
+	if h.unfurler == nil {
+		return
+	}
+	select {
+	case h.unfurlSlots <- struct{}{}:
+	default:
+		h.Log.Warn().Int64("message", msg.ID).Msg("Too many link previews in flight, skipping")
+		return
+	}
+	go func() {
+		defer func() { <-h.unfurlSlots }()
+
+		preview, ok, err := usecase.UnfurlMessage(h.logContext(), h.DBPool, h.Blobs, h.unfurler, msg)
+		if err != nil {
+			h.Log.Error().Err(err).Int64("message", msg.ID).Msg("Failed to unfurl link")
+			return
+		}
+		if !ok {
+			return
+		}
+		event := domain.PreviewEvent{ID: msg.ID, URL: preview.URL}
+		card, err := h.renderFragment("link-preview", domain.MessageView{Preview: &preview, URLs: h.URLs})
+		if err != nil {
+			h.Log.Error().Err(err).Int64("message", msg.ID).Msg("Failed to render link preview")
+		}
+		h.broadcastRendered("message_preview", event, domain.RenderedPreview{PreviewEvent: event, HTML: card})
+	}()
+}
@@ -1,14 +1,28 @@
 package v1
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"html/template"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/acakp/dumbchat/config"
 	"github.com/acakp/dumbchat/internal/adapter/templates"
 	"github.com/acakp/dumbchat/internal/controller/ws"
 	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/pkg/command"
+	"github.com/acakp/dumbchat/pkg/ratelimit"
+	"github.com/acakp/dumbchat/pkg/unfurl"
+	"github.com/acakp/dumbchat/pkg/webhook"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
 )
 
 type Handler struct {
@@ -17,6 +31,36 @@ type Handler struct {
 	Hub    *ws.Hub
 	URLs   domain.URLs
 	Tmpls  *templates.ParsedTemplates
+	Blobs  domain.BlobStore
+	// slash commands, the built-ins are registered by New
+	Commands *command.Registry
+	// set before serving
+	Hooks Hooks
+	// the chat's logger, requests log through it too. Set before serving
+	Log zerolog.Logger
+
+	reactionLimiter *ratelimit.Keyed
+	claimLimiter    *ratelimit.Keyed
+	// keyed by token ID, every token has its own rate
+	tokenLimiter *ratelimit.Keyed
+	// keyed by incoming webhook
+	hookLimiter *ratelimit.Keyed
+	// one message per interval per IP for visitors, set by /slow
+	slowMode *ratelimit.Cooldown
+	// nil when link previews are disabled
+	unfurler *unfurl.Unfurler
+	// limits the number of pages fetched at once
+	unfurlSlots chan struct{}
+	// SITE_SECRET isn't set, the secret is kept in the database
+	generatedSecret bool
+
+	webhookSender *webhook.Sender
+	// signals RunWebhooks that new deliveries are queued
+	webhookWake chan struct{}
+
+	subMu       sync.RWMutex
+	subscribers map[int]func(ws.Event)
+	nextSub     int
 }
 
 func createURLs(cfg config.Config) domain.URLs {
@@ -26,22 +70,171 @@ func createURLs(cfg config.Config) domain.URLs {
 		Base:        base,
 		Post:        base + "/messages",
 		DeleteRoute: base + "/messages/{messageID}",
-		Delete: func(id int) string {
+		Delete: func(id int64) string {
 			return fmt.Sprintf("%s/messages/%d", base, id)
 		},
+		Report: func(id int64) string {
+			return fmt.Sprintf("%s/messages/%d/report", base, id)
+		},
 		WS:      base + "/ws",
 		Message: base + "/message",
+		Admin:   base + "/admin",
+		Bans:    base + "/admin/bans",
+		DismissReports: func(id int64) string {
+			return fmt.Sprintf("%s/admin/reports/%d", base, id)
+		},
+		BulkDelete: base + "/admin/bulk-delete",
+		Lockdown:   base + "/admin/lockdown",
+		Pin: func(id int64) string {
+			return fmt.Sprintf("%s/messages/%d/pin", base, id)
+		},
+		Pinned:        base + "/pinned",
+		Announcements: base + "/admin/announcements",
+		DeleteAnnouncement: func(id int64) string {
+			return fmt.Sprintf("%s/admin/announcements/%d", base, id)
+		},
+		DeleteOwn: func(id int64) string {
+			return fmt.Sprintf("%s/messages/%d/own", base, id)
+		},
+		Update: func(id int64) string {
+			return fmt.Sprintf("%s/messages/%d", base, id)
+		},
+		Edit: func(id int64) string {
+			return fmt.Sprintf("%s/messages/%d/edit", base, id)
+		},
+		Edits: func(id int64) string {
+			return fmt.Sprintf("%s/messages/%d/edits", base, id)
+		},
+		Thread: func(id int64) string {
+			return fmt.Sprintf("%s/thread/%d", base, id)
+		},
+		React: func(id int64) string {
+			return fmt.Sprintf("%s/messages/%d/reactions", base, id)
+		},
+		Attachment: func(key string) string {
+			return fmt.Sprintf("%s/attachments/%s", base, key)
+		},
+		PreviewImage: func(key string) string {
+			return fmt.Sprintf("%s/previews/%s", base, key)
+		},
+		Search: base + "/search",
+		Permalink: func(id int64) string {
+			return fmt.Sprintf("%s/messages/%d", base, id)
+		},
+		Nicknames:       base + "/nicknames",
+		NicknameSession: base + "/nicknames/session",
+		APITokens:       base + "/admin/tokens",
+		RevokeAPIToken: func(id int64) string {
+			return fmt.Sprintf("%s/admin/tokens/%d", base, id)
+		},
+		Webhooks: base + "/admin/webhooks",
+		DeleteWebhook: func(id int64) string {
+			return fmt.Sprintf("%s/admin/webhooks/%d", base, id)
+		},
+		RetryDelivery: func(id int64) string {
+			return fmt.Sprintf("%s/admin/webhooks/deliveries/%d/retry", base, id)
+		},
+		IncomingHooks: base + "/admin/hooks",
+		DeleteIncomingHook: func(id int64) string {
+			return fmt.Sprintf("%s/admin/hooks/%d", base, id)
+		},
+		IncomingHook: func(token string) string {
+			return fmt.Sprintf("%s/hooks/%s", base, token)
+		},
 	}
 }
 
-func New(cfg config.Config, dbpool *pgxpool.Pool, hub *ws.Hub, tmpls *templates.ParsedTemplates) *Handler {
-	return &Handler{
+func New(cfg config.Config, dbpool *pgxpool.Pool, hub *ws.Hub, tmpls *templates.ParsedTemplates, blobs domain.BlobStore) *Handler {
+	hub.SetReadOnly(cfg.ReadOnly)
+
+	// replaced with the saved one by RestoreState
+	generatedSecret := cfg.SiteSecret == ""
+	if generatedSecret {
+		b := make([]byte, 32)
+		rand.Read(b)
+		cfg.SiteSecret = hex.EncodeToString(b)
+	}
+
+	h := &Handler{
 		Cfg:    cfg,
 		DBPool: dbpool,
 		Hub:    hub,
 		URLs:   createURLs(cfg),
 		Tmpls:  tmpls,
+		Blobs:  blobs,
+
+		Log:      log.Logger,
+		Commands: command.NewRegistry(),
+		// 1 reaction per second with bursts of 5 per visitor
+		reactionLimiter: ratelimit.New(1, 5),
+		// passphrase guesses: one per 10 seconds with bursts of 5 per IP
+		claimLimiter: ratelimit.New(rate.Every(10*time.Second), 5),
+		tokenLimiter: ratelimit.New(1, 60),
+		// one bot message per second with bursts of 10 per hook
+		hookLimiter: ratelimit.New(1, 10),
+
+		webhookSender: webhook.New(10 * time.Second),
+		webhookWake:   make(chan struct{}, 1),
+		slowMode:      ratelimit.NewCooldown(),
+		subscribers:   make(map[int]func(ws.Event)),
+
+		generatedSecret: generatedSecret,
+	}
+	for _, c := range h.builtinCommands() {
+		h.Commands.Register(c)
+	}
+	if cfg.LinkPreviews {
+		h.unfurler = unfurl.New(5 * time.Second)
+		h.unfurlSlots = make(chan struct{}, 4)
+	}
+	hub.Identify = h.socketNickname
+	return h
+}
+
+// a context carrying the chat's logger, for work done outside of requests
+func (h *Handler) logContext() context.Context {
+	return h.Log.WithContext(context.Background())
+}
+
+// notifies websocket hub and subscribed webhooks about an event
+func (h *Handler) broadcast(eventType string, data any) {
+	h.broadcastRendered(eventType, data, data)
+}
+
+// like broadcast, but websocket clients get rendered: data
+// with the HTML to update the page in place
+func (h *Handler) broadcastRendered(eventType string, data, rendered any) {
+	jsonData, _ := json.Marshal(ws.Event{
+		Type: eventType,
+		Data: rendered,
+	})
+	h.Hub.SendToAll(jsonData)
+	event := ws.Event{
+		Type: eventType,
+		Data: data,
+	}
+	h.publish(event)
+	h.dispatchWebhooks(eventType, data)
+}
+
+// renders a viewer-independent part of a message, see message.html
+func (h *Handler) renderFragment(name string, data any) (template.HTML, error) {
+	var b strings.Builder
+	if err := h.Tmpls.MessageTmpl.ExecuteTemplate(&b, name, data); err != nil {
+		return "", fmt.Errorf("error rendering %s: %w", name, err)
+	}
+	return template.HTML(b.String()), nil
+}
+
+// sends an event only to clients using one of the nicknames
+func (h *Handler) notify(nicknames []string, eventType string, data any) {
+	event := ws.Event{
+		Type: eventType,
+		Data: data,
 	}
+	jsonData, _ := json.Marshal(event)
+	h.Hub.SendToNicknames(nicknames, jsonData)
+	h.publish(event)
 }
 
 // func NewURLs(base string) URLs {
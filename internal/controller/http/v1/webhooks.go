@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/internal/usecase"
+	"github.com/acakp/dumbchat/pkg/render"
+)
+
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, err := usecase.ParseWebhook(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err = usecase.CreateWebhook(h.DBPool, hook); err != nil {
+		render.Error(w, r, err, http.StatusInternalServerError, "Failed to save webhook")
+		return
+	}
+	http.Redirect(w, r, h.URLs.Admin, http.StatusSeeOther)
+}
+
+// pending deliveries of the webhook are dropped with it
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := usecase.ExtractWebhookID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	err = postgres.DeleteWebhook(h.DBPool, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Webhook not found")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// queues a dead delivery again
+func (h *Handler) RetryWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := usecase.ExtractDeliveryID(r)
+	if err != nil {
+		render.Error(w, r, err, http.StatusBadRequest, "Bad request")
+		return
+	}
+
+	err = postgres.RetryWebhookDelivery(h.DBPool, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			render.Error(w, r, err, http.StatusNotFound, "Delivery not found or not dead")
+		} else {
+			render.Error(w, r, err, http.StatusInternalServerError, "Internal Server Error")
+		}
+		return
+	}
+	select {
+	case h.webhookWake <- struct{}{}:
+	default:
+	}
+	w.WriteHeader(http.StatusOK)
+}
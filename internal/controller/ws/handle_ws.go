@@ -1,15 +1,21 @@
 package ws
 
 import (
-	"net"
+	"crypto/rand"
+	"encoding/hex"
 	"net/http"
-	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/acakp/dumbchat/internal/usecase"
 	"github.com/acakp/dumbchat/pkg/render"
 	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/hlog"
 	"golang.org/x/time/rate"
 )
 
+const maxCloseReason = 123
+
 func HandleWS(hub *Hub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		upgrader := websocket.Upgrader{
@@ -18,43 +24,78 @@ func HandleWS(hub *Hub) http.HandlerFunc {
 			},
 		}
 
-		conn, err := upgrader.Upgrade(w, r, nil)
+		clientIp := usecase.ClientIP(r)
+		client := &Client{
+			id:   newClientID(),
+			ip:   clientIp,
+			hub:  hub,
+			send: make(chan []byte, 16),
+			rate: rate.NewLimiter(1, 5),
+		}
+
+		// checked before the upgrade, the response can't be written after it
+		err := hub.trackConnection(clientIp)
 		if err != nil {
-			render.Error(w, err, http.StatusUpgradeRequired, "Error upgrading to websockets")
+			render.Error(w, r, err, http.StatusTooManyRequests, "Too many connections")
 			return
 		}
+		if hub.Identify != nil {
+			client.setNickname(hub.Identify(r))
+		}
 
-		clientIp := clientIP(r)
-		err = hub.trackConnection(clientIp)
+		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
-			render.Error(w, err, http.StatusTooManyRequests, "Too many connections")
+			// Upgrade has already responded
+			hlog.FromRequest(r).Debug().Err(err).Msg("Error upgrading to websockets")
+			hub.releaseConnection(clientIp)
 			return
 		}
-		client := &Client{
-			ip:   clientIp,
-			hub:  hub,
-			conn: conn,
-			send: make(chan []byte),
-			rate: rate.NewLimiter(1, 5),
+		client.conn = conn
+		client.send <- helloEvent(client.id)
+		if !hub.register(client) {
+			hub.releaseConnection(clientIp)
+			conn.Close()
+			return
+		}
+
+		// readPump calls OnDisconnect for accepted connections only
+		if hub.OnConnect != nil {
+			if err := hub.OnConnect(r, client.Info()); err != nil {
+				hub.unregister(client)
+				hub.releaseConnection(clientIp)
+				refuse(conn, err.Error())
+				return
+			}
 		}
-		hub.Register <- client
 
 		go client.writePump(hub)
 		go client.readPump(hub)
 	}
 }
 
-func clientIP(r *http.Request) string {
-	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+// closes a connection refused by OnConnect, the reason is shown to the client
+func refuse(conn *websocket.Conn, reason string) {
+	reason = truncateReason(reason)
+	msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+	conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	conn.Close()
+}
+
+// unguessable, so other visitors can't read replies meant for this connection
+func newClientID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
 
-	if ip == "127.0.0.1" {
-		if real := r.Header.Get("X-Real-IP"); real != "" {
-			return real
-		}
-		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-			return strings.Split(xff, ",")[0]
-		}
+// close frames carry at most 123 bytes of text, which must be valid UTF-8
+func truncateReason(reason string) string {
+	if len(reason) <= maxCloseReason {
+		return reason
 	}
-
-	return ip
+	n := maxCloseReason
+	for n > 0 && !utf8.RuneStart(reason[n]) {
+		n--
+	}
+	return reason[:n]
 }
@@ -0,0 +1,29 @@
+package ws
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateReason(t *testing.T) {
+	tests := []struct {
+		name, in string
+		want     int
+	}{
+		{"short", "sign in to chat", 15},
+		{"at the limit", strings.Repeat("a", 123), 123},
+		{"ascii", strings.Repeat("a", 200), 123},
+		// 61 two-byte letters end at 122, the next one doesn't fit
+		{"split letter", strings.Repeat("я", 100), 122},
+		// 30 four-byte emoji end at 120
+		{"split emoji", strings.Repeat("🙂", 40), 120},
+		{"emoji after ascii", "a" + strings.Repeat("🙂", 40), 121},
+	}
+	for _, tt := range tests {
+		got := truncateReason(tt.in)
+		if len(got) != tt.want || !utf8.ValidString(got) || !strings.HasPrefix(tt.in, got) {
+			t.Errorf("%s: truncateReason gave %d bytes %q, want %d valid bytes", tt.name, len(got), got, tt.want)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package ws
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,11 +14,41 @@ import (
 
 type Hub struct {
 	mu         sync.Mutex
+	readOnly   bool
 	IpCounts   map[string]int
 	Clients    map[*Client]bool
 	Register   chan *Client
 	Unregister chan *Client
 	Broadcast  chan []byte
+	direct     chan directMessage
+	// closed by Close, stops Run and unblocks senders
+	quit      chan struct{}
+	closeOnce sync.Once
+
+	// returns the nickname a new connection gets targeted events for,
+	// derived from what the request proves rather than what it claims.
+	// Without it connections only get a nickname once they post
+	Identify func(r *http.Request) string
+	// called once a connection is open, an error closes it
+	// with the error text as the close reason
+	OnConnect func(r *http.Request, c ClientInfo) error
+	// called after a connection accepted by OnConnect is closed
+	OnDisconnect func(c ClientInfo)
+}
+
+// describes a connection to the connect hooks
+type ClientInfo struct {
+	ID string
+	IP string
+	// the registered nickname the visitor is signed in to, else the one
+	// they last posted under. Empty for visitors who haven't posted yet
+	Nickname string
+}
+
+// message delivered only to clients accepted by match
+type directMessage struct {
+	match func(c *Client) bool
+	msg   []byte
 }
 
 type Client struct {
@@ -24,6 +57,14 @@ type Client struct {
 	conn *websocket.Conn
 	send chan []byte
 	rate *rate.Limiter
+
+	// random ID sent to the widget in the hello event,
+	// lets HTTP handlers reply to this connection only
+	id string
+
+	mu sync.Mutex
+	// nickname the visitor posts under, used for targeted events
+	nickname string
 }
 
 type Event struct {
@@ -31,6 +72,15 @@ type Event struct {
 	Data any    `json:"data"`
 }
 
+// first event on every connection, tells the widget its client ID
+func helloEvent(id string) []byte {
+	jsonData, _ := json.Marshal(Event{
+		Type: "hello",
+		Data: map[string]string{"clientId": id},
+	})
+	return jsonData
+}
+
 func New() *Hub {
 	return &Hub{
 		IpCounts:   make(map[string]int),
@@ -38,6 +88,8 @@ func New() *Hub {
 		Register:   make(chan *Client),
 		Unregister: make(chan *Client),
 		Broadcast:  make(chan []byte),
+		direct:     make(chan directMessage),
+		quit:       make(chan struct{}),
 	}
 }
 
@@ -75,32 +127,163 @@ func (h *Hub) trackConnection(ip string) error {
 	return nil
 }
 
+// in read-only mode only admins may post, edit or react
+func (h *Hub) SetReadOnly(readOnly bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readOnly = readOnly
+}
+
+func (h *Hub) ReadOnly() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.readOnly
+}
+
+// Run delivers messages until Close is called
 func (h *Hub) Run() {
 	for {
 		select {
+		case <-h.quit:
+			// lets the write pumps send a close frame
+			for c := range h.Clients {
+				delete(h.Clients, c)
+				close(c.send)
+			}
+			return
 		case c := <-h.Register:
 			h.Clients[c] = true
 		case c := <-h.Unregister:
-			delete(h.Clients, c)
-			close(c.send)
+			// client may already be dropped by a failed send
+			if h.Clients[c] {
+				delete(h.Clients, c)
+				close(c.send)
+			}
 		case msg := <-h.Broadcast:
 			for c := range h.Clients {
-				select {
-				case c.send <- msg:
-				default:
-					close(c.send)
-					delete(h.Clients, c)
+				h.deliver(c, msg)
+			}
+		case d := <-h.direct:
+			for c := range h.Clients {
+				if d.match(c) {
+					h.deliver(c, d.msg)
 				}
 			}
 		}
 	}
 }
 
+// Close stops Run and disconnects all clients. Messages sent
+// after it are dropped, new connections are refused
+func (h *Hub) Close() {
+	h.closeOnce.Do(func() { close(h.quit) })
+}
+
+// delivers msg to all clients
+func (h *Hub) SendToAll(msg []byte) {
+	select {
+	case h.Broadcast <- msg:
+	case <-h.quit:
+	}
+}
+
+// returns false if the hub is closed
+func (h *Hub) register(c *Client) bool {
+	select {
+	case h.Register <- c:
+		return true
+	case <-h.quit:
+		return false
+	}
+}
+
+func (h *Hub) unregister(c *Client) {
+	select {
+	case h.Unregister <- c:
+	case <-h.quit:
+	}
+}
+
+// drops the client if its send buffer is full
+func (h *Hub) deliver(c *Client, msg []byte) {
+	select {
+	case c.send <- msg:
+	default:
+		close(c.send)
+		delete(h.Clients, c)
+	}
+}
+
+func (h *Hub) sendTo(match func(c *Client) bool, msg []byte) {
+	select {
+	case h.direct <- directMessage{match: match, msg: msg}:
+	case <-h.quit:
+	}
+}
+
+// delivers msg only to clients identified with one of the nicknames
+func (h *Hub) SendToNicknames(nicknames []string, msg []byte) {
+	h.sendTo(func(c *Client) bool {
+		nickname := c.Nickname()
+		for _, n := range nicknames {
+			if strings.EqualFold(n, nickname) {
+				return true
+			}
+		}
+		return false
+	}, msg)
+}
+
+// sets the nickname targeted events are delivered to for the connection
+// with the given ID, after it posted under the nickname
+func (h *Hub) SetClientNickname(id, nickname string) {
+	if id == "" {
+		return
+	}
+	// matches no client, so nothing is sent: the match only runs
+	// in Run, which owns the clients
+	h.sendTo(func(c *Client) bool {
+		if c.id == id {
+			c.setNickname(nickname)
+		}
+		return false
+	}, nil)
+}
+
+// delivers msg only to the connection with the given ID
+func (h *Hub) SendToClient(id string, msg []byte) {
+	if id == "" {
+		return
+	}
+	h.sendTo(func(c *Client) bool { return c.id == id }, msg)
+}
+
+func (c *Client) ID() string {
+	return c.id
+}
+
+func (c *Client) Info() ClientInfo {
+	return ClientInfo{ID: c.id, IP: c.ip, Nickname: c.Nickname()}
+}
+
+func (c *Client) Nickname() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nickname
+}
+
+func (c *Client) setNickname(nickname string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// drop the tripcode secret, only the name is matched
+	name, _, _ := strings.Cut(nickname, "#")
+	c.nickname = strings.TrimSpace(name)
+}
+
 func (c *Client) writePump(h *Hub) {
 	ticker := time.NewTicker(30 * time.Second)
 	defer func() {
 		ticker.Stop()
-		h.releaseConnection(c.ip)
 		c.conn.Close()
 	}()
 	for {
@@ -124,9 +307,12 @@ func (c *Client) writePump(h *Hub) {
 
 func (c *Client) readPump(h *Hub) {
 	defer func() {
-		h.Unregister <- c
+		h.unregister(c)
 		h.releaseConnection(c.ip)
 		c.conn.Close()
+		if h.OnDisconnect != nil {
+			h.OnDisconnect(c.Info())
+		}
 	}()
 
 	c.conn.SetReadLimit(4096)
@@ -138,10 +324,10 @@ func (c *Client) readPump(h *Hub) {
 			return
 		}
 
-		_, msg, err := c.conn.ReadMessage()
-		if err != nil {
+		// clients never send events to each other, and the nickname
+		// comes from the server, so frames are only read to detect closing
+		if _, _, err := c.conn.ReadMessage(); err != nil {
 			break
 		}
-		c.hub.Broadcast <- msg
 	}
 }
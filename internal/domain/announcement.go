@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// admin-authored notice shown above the chat until it expires
+// or is removed. Nil ExpiresAt means it never expires
+type Announcement struct {
+	ID        int64      `json:"id"`
+	Content   string     `json:"content"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// sticky area above the chat with pinned messages and announcements
+type PinnedView struct {
+	Messages      []Message
+	Announcements []Announcement
+	IsAdmin       bool
+	URLs          URLs
+}
+
+func (p PinnedView) IsEmpty() bool {
+	return len(p.Messages) == 0 && len(p.Announcements) == 0
+}
+
+const (
+	PinKindMessage      = "message"
+	PinKindAnnouncement = "announcement"
+)
+
+// payload of pin/unpin websocket events
+type PinEvent struct {
+	Kind string `json:"kind"`
+	ID   int64  `json:"id"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// page of messages requested through the JSON API,
+// zero-valued fields are not used for filtering
+type MessagePageQuery struct {
+	// only messages with smaller IDs, the newest page if both cursors are zero
+	Before int64
+	// only messages with bigger IDs
+	After    int64
+	Limit    int
+	Nickname string
+	From     time.Time
+	To       time.Time
+}
+
+// message as returned by the JSON API
+type APIMessage struct {
+	Message
+	Reactions   []ReactionCount `json:"reactions"`
+	Attachments []APIAttachment `json:"attachments"`
+	URL         string          `json:"url"`
+}
+
+type APIAttachment struct {
+	Filename string `json:"filename"`
+	MIME     string `json:"mime"`
+	Size     int64  `json:"size"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	URL      string `json:"url"`
+	// set for images only
+	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+}
+
+// messages ordered from oldest to newest
+type APIMessagePage struct {
+	Messages []APIMessage `json:"messages"`
+	// more messages are available in the direction of the page
+	HasMore bool `json:"hasMore"`
+}
+
+// body of the message creation request
+type APINewMessage struct {
+	// may be entered as "name#secret" to get a tripcode
+	Nickname string `json:"nickname,omitempty"`
+	Content  string `json:"content"`
+	ReplyTo  *int64 `json:"replyTo,omitempty"`
+}
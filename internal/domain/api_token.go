@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// what an API token may be used for
+const (
+	// reading messages, hidden ones need ScopeModerate
+	ScopeRead = "read"
+	ScopePost = "post"
+	// everything admins can do
+	ScopeModerate = "moderate"
+)
+
+var APIScopes = []string{ScopeRead, ScopePost, ScopeModerate}
+
+func IsAPIScope(scope string) bool {
+	return slices.Contains(APIScopes, scope)
+}
+
+// admin-issued token for bots and integrations,
+// only a hash of the token itself is stored
+type APIToken struct {
+	ID   int64
+	Name string
+	// beginning of the token to tell tokens apart
+	Prefix string
+	Scopes []string
+	// requests per minute
+	RateLimit  int
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+func (t APIToken) HasScope(scope string) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+func (t APIToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// token row on the admin page, Secret is only set right after creation
+type APITokenView struct {
+	Token  APIToken
+	Secret string
+	URLs   URLs
+}
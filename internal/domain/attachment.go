@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// file uploaded together with a message.
+// Images also have a thumbnail stored under ThumbKey
+type Attachment struct {
+	ID        int64     `json:"id"`
+	MessageID int64     `json:"message_id"`
+	Key       string    `json:"key"`
+	ThumbKey  string    `json:"thumb_key,omitempty"`
+	Filename  string    `json:"filename"`
+	MIME      string    `json:"mime"`
+	Size      int64     `json:"size"`
+	Width     int       `json:"width,omitempty"`
+	Height    int       `json:"height,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (a Attachment) IsImage() bool {
+	return a.ThumbKey != ""
+}
+
+// size in a human-readable form, e.g. "1.2 MB"
+func (a Attachment) FormattedSize() string {
+	const unit = 1024
+	if a.Size < unit {
+		return fmt.Sprintf("%d B", a.Size)
+	}
+	div, exp := int64(unit), 0
+	for n := a.Size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(a.Size)/float64(div), "KMGT"[exp])
+}
+
+// validated upload ready to be stored
+type Upload struct {
+	Attachment Attachment
+	Data       []byte
+	// empty for files that aren't images
+	Thumb    []byte
+	ThumbExt string
+}
+
+// BlobStore keeps the contents of uploaded files, addressed by opaque keys
+type BlobStore interface {
+	Put(key string, r io.Reader) error
+	// returns ErrNotFound for unknown keys
+	Open(key string) (io.ReadCloser, error)
+	// deleting a missing blob is not an error
+	Delete(key string) error
+}
@@ -0,0 +1,14 @@
+package domain
+
+// ephemeral answer to a slash command, shown only to the issuer
+type CommandReply struct {
+	Command string `json:"command"`
+	Text    string `json:"text"`
+	// nickname the issuer's widget should switch to, set by /nick
+	Nickname string `json:"nickname,omitempty"`
+}
+
+// payload of slow_mode websocket events, 0 seconds turns it off
+type SlowModeState struct {
+	Seconds int `json:"seconds"`
+}
@@ -4,3 +4,17 @@ import "errors"
 
 var ErrMessageNotFound = errors.New("message with given ID not found")
 var ErrNotFound = errors.New("not found")
+var ErrAlreadyReported = errors.New("message already reported by this user")
+var ErrBanned = errors.New("user is banned")
+var ErrReadOnly = errors.New("chat is in read-only mode")
+var ErrEmptyFilter = errors.New("at least one filter is required")
+var ErrInvalidPattern = errors.New("invalid pattern")
+var ErrFileTooLarge = errors.New("file is too large")
+var ErrUnsupportedFileType = errors.New("file type is not allowed")
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+var ErrNicknameReserved = errors.New("nickname is registered by someone else")
+var ErrPassphraseTooShort = errors.New("passphrase is too short")
+var ErrInvalidToken = errors.New("invalid or revoked API token")
+var ErrMissingScope = errors.New("API token lacks the required scope")
+var ErrUnknownIP = errors.New("message has no IP address")
+var ErrSlowMode = errors.New("slow mode is on")
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// secret URL scripts post bot messages to,
+// only a hash of its token is stored
+type IncomingWebhook struct {
+	ID int64
+	// default display name of the messages
+	Name string
+	// beginning of the token to tell hooks apart
+	Prefix     string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+// body of incoming webhook requests
+type IncomingWebhookPayload struct {
+	Text string `json:"text"`
+	// overrides the webhook's name for this message
+	Name string `json:"name,omitempty"`
+}
+
+// hook row on the admin page, URL is only set right after creation
+type IncomingWebhookView struct {
+	Hook IncomingWebhook
+	URL  string
+	URLs URLs
+}
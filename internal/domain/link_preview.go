@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"html/template"
+	"time"
+)
+
+// cached metadata of a linked page, shown as a card under the message
+type LinkPreview struct {
+	URL         string
+	Title       string
+	Description string
+	ImageURL    string
+	// blob key of the downloaded image, empty if there's none.
+	// Images are served by the chat, never hot-linked
+	ImageKey  string
+	SiteName  string
+	FetchedAt time.Time
+	// the page couldn't be fetched, kept so it isn't retried on every message
+	Failed bool
+}
+
+// payload of message_preview websocket events
+type PreviewEvent struct {
+	ID  int64  `json:"id"`
+	URL string `json:"url"`
+}
+
+// message_preview payload sent to websocket clients, with the rendered card
+type RenderedPreview struct {
+	PreviewEvent
+	HTML template.HTML `json:"html"`
+}
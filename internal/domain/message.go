@@ -1,42 +1,171 @@
 package domain
 
-import "time"
+import (
+	"html/template"
+	"time"
+)
 
 type Message struct {
-	ID        int64     `json:"id"`
-	Nickname  string    `json:"nickname"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"createdAt"`
+	ID         int64      `json:"id"`
+	Nickname   string     `json:"nickname"`
+	Content    string     `json:"content"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	IP         string     `json:"-"`
+	Hidden     bool       `json:"hidden"`
+	Pinned     bool       `json:"pinned"`
+	AuthorHash string     `json:"-"`
+	EditedAt   *time.Time `json:"editedAt,omitempty"`
+	ReplyTo    *int64     `json:"replyTo,omitempty"`
+	// posted under a registered nickname by its owner
+	Verified bool `json:"verified,omitempty"`
+	// keyed hash of the secret the nickname was entered with, see usecase.Tripcode
+	Tripcode string `json:"tripcode,omitempty"`
+	// who posted the message, one of the MessageKind constants
+	Kind string `json:"kind"`
+	// parent message summary, set when ReplyTo is set
+	ReplyPreview MessagePreview `json:"-"`
+}
+
+// message kinds
+const (
+	// posted by a visitor through the chat form or the API
+	MessageKindUser = "user"
+	// posted through an incoming webhook
+	MessageKindBot = "bot"
+	// posted with /me, rendered as "* nickname text"
+	MessageKindAction = "action"
+)
+
+func (m Message) IsBot() bool {
+	return m.Kind == MessageKindBot
+}
+
+func (m Message) IsAction() bool {
+	return m.Kind == MessageKindAction
+}
+
+// short quote of a message shown above its replies
+type MessagePreview struct {
+	Nickname string
+	Content  string
+	// the quoted message is hidden, only admins see the quote
+	Hidden bool
 }
 
 func (m Message) FormattedTime() string {
 	return m.CreatedAt.Format("15:04 02.01.06")
 }
 
+func (m Message) IsEdited() bool {
+	return m.EditedAt != nil
+}
+
+// ID of the parent message, 0 if the message isn't a reply
+func (m Message) ParentID() int64 {
+	if m.ReplyTo == nil {
+		return 0
+	}
+	return *m.ReplyTo
+}
+
 type URLs struct {
-	Base        string
-	Post        string
-	Poll        string
-	DeleteRoute string
-	Delete      func(id int) string
-	WS          string
-	Message     string
+	Base               string
+	Post               string
+	Poll               string
+	DeleteRoute        string
+	Delete             func(id int64) string
+	Report             func(id int64) string
+	WS                 string
+	Message            string
+	Admin              string
+	Bans               string
+	DismissReports     func(id int64) string
+	BulkDelete         string
+	Lockdown           string
+	Pin                func(id int64) string
+	Pinned             string
+	Announcements      string
+	DeleteAnnouncement func(id int64) string
+	DeleteOwn          func(id int64) string
+	Update             func(id int64) string
+	Edit               func(id int64) string
+	Edits              func(id int64) string
+	Thread             func(id int64) string
+	React              func(id int64) string
+	Attachment         func(key string) string
+	PreviewImage       func(key string) string
+	Search             string
+	Permalink          func(id int64) string
+	Nicknames          string
+	NicknameSession    string
+	APITokens          string
+	RevokeAPIToken     func(id int64) string
+	Webhooks           string
+	DeleteWebhook      func(id int64) string
+	RetryDelivery      func(id int64) string
+	IncomingHooks      string
+	DeleteIncomingHook func(id int64) string
+	// path of an incoming webhook with the given token
+	IncomingHook func(token string) string
 }
 
 type ChatView struct {
 	Messages []MessageView
 	IsAdmin  bool
+	ReadOnly bool
+	Pinned   PinnedView
+	// value for the accept attribute of the file input,
+	// empty when uploads are disabled
+	UploadAccept string
+	// message a permalink page is centred on, zero on the live chat
+	FocusID int64
+	// maxlength hints for the form fields
+	MaxMessageLength  int
+	MaxNicknameLength int
+	URLs              URLs
+}
+
+// a message with all its replies (and replies to them)
+type ThreadView struct {
+	Messages []MessageView
 	URLs     URLs
 }
+
+// payload of mention websocket events,
+// sent only to clients using one of the mentioned nicknames
+type MentionEvent struct {
+	ID        int64    `json:"id"`
+	Nickname  string   `json:"nickname"`
+	Mentioned []string `json:"mentioned"`
+}
+
+// edit_message payload sent to websocket clients: the message
+// with its rendered line, swapped in without refetching the message
+type RenderedEdit struct {
+	Message
+	Line template.HTML `json:"line"`
+}
+
+type LockdownState struct {
+	ReadOnly bool `json:"readOnly"`
+}
 type MessageView struct {
-	URLs    URLs
-	Msg     Message
-	IsAdmin bool
+	URLs URLs
+	Msg  Message
+	// rendered message content
+	Body        template.HTML
+	IsAdmin     bool
+	IsAuthor    bool
+	CanEdit     bool
+	Reactions   []ReactionCount
+	Attachments []Attachment
+	// preview of the first link in the message, if any
+	Preview *LinkPreview
+	// maxlength hint for the edit form
+	MaxLength int
 }
 
-func (m *Message) TruncateMessageContent() {
-	maxLen := 4000
-	if len(m.Content) > maxLen {
-		m.Content = m.Content[:maxLen]
-	}
+// emojis offered in the reaction picker
+func (v MessageView) ReactionChoices() []string {
+	return ReactionEmojis
 }
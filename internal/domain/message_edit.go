@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+const (
+	EditorAuthor = "author"
+	EditorAdmin  = "admin"
+)
+
+// previous version of an edited message
+type MessageEdit struct {
+	ID         int64
+	MessageID  int64
+	OldContent string
+	EditedAt   time.Time
+	EditedBy   string
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// selects messages for bulk moderation,
+// zero-valued fields are not used for filtering
+type MessageFilter struct {
+	Nickname string
+	IP       string
+	From     time.Time
+	To       time.Time
+	Pattern  string  // POSIX regular expression matched against content
+	IDs      []int64 // picked messages, like the newest ones for /clear
+}
+
+func (f MessageFilter) IsEmpty() bool {
+	return f.Nickname == "" && f.IP == "" && f.From.IsZero() && f.To.IsZero() && f.Pattern == "" && len(f.IDs) == 0
+}
+
+type BulkDeleteResult struct {
+	IDs []int64 `json:"ids"`
+}
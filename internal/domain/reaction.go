@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// emojis visitors can react with
+var ReactionEmojis = []string{"👍", "❤️", "😂", "😮", "😢", "🎉"}
+
+func IsReactionEmoji(emoji string) bool {
+	for _, e := range ReactionEmojis {
+		if e == emoji {
+			return true
+		}
+	}
+	return false
+}
+
+type Reaction struct {
+	MessageID int64
+	Emoji     string
+	// author token hash or IP hash of the visitor
+	Reactor   string
+	CreatedAt time.Time
+}
+
+type ReactionCount struct {
+	Emoji string `json:"emoji"`
+	Count int    `json:"count"`
+	// whether the viewer is among the reactors
+	Mine bool `json:"-"`
+}
+
+// payload of reaction_update websocket events
+type ReactionUpdate struct {
+	ID        int64           `json:"id"`
+	Reactions []ReactionCount `json:"reactions"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+type Report struct {
+	ID         int64
+	MessageID  int64
+	Reason     string
+	ReporterIP string
+	CreatedAt  time.Time
+}
+
+// a reported message together with all reports filed against it,
+// as shown in the admin reports inbox
+type ReportedMessage struct {
+	Msg     Message
+	Reports []Report
+}
+
+func (rm ReportedMessage) Count() int {
+	return len(rm.Reports)
+}
+
+type Ban struct {
+	ID        int64
+	IP        string
+	Nickname  string
+	Reason    string
+	CreatedAt time.Time
+}
+
+type AdminView struct {
+	Reports       []ReportedMessage
+	Announcements []Announcement
+	APITokens     []APITokenView
+	Webhooks      []Webhook
+	IncomingHooks []IncomingWebhookView
+	// latest deliveries of all webhooks
+	Deliveries []WebhookDelivery
+	// deliveries that ran out of retries
+	DeadDeliveries []WebhookDelivery
+	ReadOnly       bool
+	URLs           URLs
+}
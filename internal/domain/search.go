@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"html/template"
+	"time"
+)
+
+type SearchQuery struct {
+	// words to look for, supports "quoted phrases", OR and -exclusions
+	Text     string
+	Nickname string
+	From     time.Time
+	To       time.Time
+}
+
+func (q SearchQuery) IsEmpty() bool {
+	return q.Text == "" && q.Nickname == "" && q.From.IsZero() && q.To.IsZero()
+}
+
+// markers put around matches in search fragments,
+// control characters never appear in rendered messages
+const (
+	SearchMarkStart = "\x02"
+	SearchMarkStop  = "\x03"
+)
+
+type SearchResult struct {
+	Msg Message
+	// plain text fragment with matches between the search marks
+	Fragment string
+	// escaped fragment with matches wrapped into <mark>
+	Headline template.HTML
+}
+
+type SearchView struct {
+	Query   SearchQuery
+	Results []SearchResult
+	// more messages match than shown
+	Truncated bool
+	URLs      URLs
+}
@@ -0,0 +1,14 @@
+package domain
+
+// the visitor a page is rendered for
+type Viewer struct {
+	IsAdmin bool
+	// hash of the visitor's author token, empty if they never posted
+	AuthorHash string
+	// identifies the visitor in reactions: author hash or IP hash
+	ReactorID string
+}
+
+func (v Viewer) IsAuthorOf(m Message) bool {
+	return v.AuthorHash != "" && v.AuthorHash == m.AuthorHash
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// events webhooks can subscribe to
+const (
+	EventNewMessage     = "new_message"
+	EventDeleteMessage  = "delete_message"
+	EventDeleteMessages = "delete_messages"
+	EventReport         = "report"
+	EventBan            = "ban"
+)
+
+var WebhookEvents = []string{EventNewMessage, EventDeleteMessage, EventDeleteMessages, EventReport, EventBan}
+
+func IsWebhookEvent(event string) bool {
+	return slices.Contains(WebhookEvents, event)
+}
+
+// outgoing webhook registered by an admin
+type Webhook struct {
+	ID  int64
+	URL string
+	// key of the HMAC signature, shared with the receiver
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+func (w Webhook) Subscribes(event string) bool {
+	return slices.Contains(w.Events, event)
+}
+
+// delivery states
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	// gave up after the last retry, can be retried by an admin
+	DeliveryDead = "dead"
+)
+
+// one event sent to one webhook, kept as the delivery log
+type WebhookDelivery struct {
+	ID        int64
+	WebhookID int64
+	// URL and Secret of the webhook
+	URL           string
+	Secret        string
+	Event         string
+	Payload       string
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	// HTTP status of the last attempt, 0 if there was no response
+	LastStatus  int
+	LastError   string
+	CreatedAt   time.Time
+	DeliveredAt *time.Time
+}
+
+// body of webhook requests
+type WebhookPayload struct {
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// data of report webhook events
+type ReportEvent struct {
+	MessageID int64  `json:"messageId"`
+	Reason    string `json:"reason"`
+	// reports of the message so far
+	Count     int       `json:"count"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// data of ban webhook events
+type BanEvent struct {
+	MessageID int64     `json:"messageId"`
+	Nickname  string    `json:"nickname"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const apiTokenPrefix = "dct_"
+
+type apiTokenKey struct{}
+
+// generates the token, only its hash and first characters are stored.
+// The returned secret can't be recovered later
+func CreateAPIToken(db *pgxpool.Pool, t domain.APIToken) (domain.APIToken, string, error) {
+	secret := randomToken(apiTokenPrefix)
+	t.Prefix = tokenPrefix(secret, apiTokenPrefix)
+
+	var err error
+	t.ID, err = postgres.InsertAPIToken(db, t, AuthorHash(secret))
+	if err != nil {
+		return domain.APIToken{}, "", fmt.Errorf("CreateAPIToken: %w", err)
+	}
+	return t, secret, nil
+}
+
+// returns the token from the Authorization header, if any
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// looks up an active token and records its use
+func AuthenticateAPIToken(db *pgxpool.Pool, secret string) (domain.APIToken, error) {
+	t, err := postgres.GetAPITokenByHash(db, AuthorHash(secret))
+	if err != nil {
+		return domain.APIToken{}, err
+	}
+	if t.IsRevoked() {
+		return domain.APIToken{}, domain.ErrInvalidToken
+	}
+	if err = postgres.TouchAPIToken(db, t.ID); err != nil {
+		return domain.APIToken{}, err
+	}
+	return t, nil
+}
+
+func WithAPIToken(ctx context.Context, t domain.APIToken) context.Context {
+	return context.WithValue(ctx, apiTokenKey{}, t)
+}
+
+// returns the token the request was authenticated with
+func APITokenFrom(ctx context.Context) (domain.APIToken, bool) {
+	t, ok := ctx.Value(apiTokenKey{}).(domain.APIToken)
+	return t, ok
+}
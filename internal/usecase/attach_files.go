@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// loads attachments for all views in one query
+func AttachFiles(db *pgxpool.Pool, views []domain.MessageView) error {
+	if len(views) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(views))
+	for _, v := range views {
+		ids = append(ids, v.Msg.ID)
+	}
+
+	attachments, err := postgres.GetAttachments(db, ids)
+	if err != nil {
+		return fmt.Errorf("AttachFiles: %w", err)
+	}
+	for i := range views {
+		views[i].Attachments = attachments[views[i].Msg.ID]
+	}
+	return nil
+}
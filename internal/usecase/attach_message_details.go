@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// loads everything rendered along with the messages:
+// reactions, attachments and link previews
+func AttachMessageDetails(db *pgxpool.Pool, views []domain.MessageView, reactor string) error {
+	if err := AttachReactions(db, views, reactor); err != nil {
+		return err
+	}
+	if err := AttachFiles(db, views); err != nil {
+		return err
+	}
+	return AttachPreviews(db, views)
+}
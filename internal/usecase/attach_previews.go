@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/pkg/markdown"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// sets cached previews of the first link in every message.
+// Links that haven't been fetched yet are skipped
+func AttachPreviews(db *pgxpool.Pool, views []domain.MessageView) error {
+	links := make(map[int]string)
+	var urls []string
+	for i, v := range views {
+		if found := markdown.Links(v.Msg.Content); len(found) > 0 {
+			links[i] = found[0]
+			urls = append(urls, found[0])
+		}
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	previews, err := postgres.GetLinkPreviews(db, urls)
+	if err != nil {
+		return fmt.Errorf("AttachPreviews: %w", err)
+	}
+	for i, url := range links {
+		if p, ok := previews[url]; ok && !p.Failed {
+			views[i].Preview = &p
+		}
+	}
+	return nil
+}
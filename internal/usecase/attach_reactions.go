@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// loads reaction counts for all views in one query
+func AttachReactions(db *pgxpool.Pool, views []domain.MessageView, reactor string) error {
+	if len(views) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(views))
+	for _, v := range views {
+		ids = append(ids, v.Msg.ID)
+	}
+
+	reactions, err := postgres.GetReactions(db, ids, reactor)
+	if err != nil {
+		return fmt.Errorf("AttachReactions: %w", err)
+	}
+	for i := range views {
+		views[i].Reactions = reactions[views[i].Msg.ID]
+	}
+	return nil
+}
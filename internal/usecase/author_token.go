@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+const authorTokenCookie = "author_token"
+
+// returns the visitor's author token if the cookie carries a valid signature
+func AuthorToken(r *http.Request, secret string) (string, bool) {
+	return readSignedToken(r, authorTokenCookie, secret)
+}
+
+// reuses the visitor's author token or issues a new one,
+// so every browser keeps a single token for all its messages
+func IssueAuthorToken(w http.ResponseWriter, r *http.Request, secret string) string {
+	if token, ok := AuthorToken(r, secret); ok {
+		return token
+	}
+	return issueSignedToken(w, authorTokenCookie, secret)
+}
+
+// only the hash of the token is stored alongside messages
+func AuthorHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns true if the visitor is signed in to the registered nickname,
+// and domain.ErrNicknameReserved if it's registered by someone else
+func CheckNicknameOwner(db *pgxpool.Pool, r *http.Request, nickname, secret string) (bool, error) {
+	registered, owned, err := postgres.GetNicknameStatus(db, nickname, IdentityHash(r, secret))
+	if err != nil {
+		return false, fmt.Errorf("CheckNicknameOwner: %w", err)
+	}
+	if registered && !owned {
+		return false, domain.ErrNicknameReserved
+	}
+	return owned, nil
+}
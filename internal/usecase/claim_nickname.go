@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const MinPassphraseLength = 8
+
+// registers a free nickname or signs in to an already registered one.
+// Returns true if the nickname was registered by this call
+func ClaimNickname(db *pgxpool.Pool, nickname, passphrase, identityHash string) (bool, error) {
+	if len(passphrase) < MinPassphraseLength {
+		return false, domain.ErrPassphraseTooShort
+	}
+	hash, err := postgres.GetNicknamePassphraseHash(db, nickname)
+	registered := false
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		newHash, err := bcrypt.GenerateFromPassword([]byte(passphrase), bcrypt.DefaultCost)
+		if err != nil {
+			return false, fmt.Errorf("ClaimNickname: %w", err)
+		}
+		registered, err = postgres.InsertNickname(db, nickname, string(newHash))
+		if err != nil {
+			return false, fmt.Errorf("ClaimNickname: %w", err)
+		}
+		if !registered {
+			// registered by someone else in the meantime
+			return false, domain.ErrNicknameReserved
+		}
+	case err != nil:
+		return false, fmt.Errorf("ClaimNickname: %w", err)
+	default:
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(passphrase)) != nil {
+			return false, domain.ErrWrongPassphrase
+		}
+	}
+
+	if err = postgres.InsertNicknameSession(db, identityHash, nickname); err != nil {
+		return false, fmt.Errorf("ClaimNickname: %w", err)
+	}
+	return registered, nil
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// deletes blobs of attachments whose messages were deleted.
+// Errors are only logged to the ctx logger, the leftovers are picked up on the next run.
+// Handlers run it in the background; concurrent runs don't overlap,
+// every orphan is deleted from the db by only one of them
+func CleanupAttachments(ctx context.Context, db *pgxpool.Pool, blobs domain.BlobStore) {
+	attachments, err := postgres.DeleteOrphanAttachments(db)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to clean up attachments")
+		return
+	}
+	for _, a := range attachments {
+		for _, key := range []string{a.Key, a.ThumbKey} {
+			if key == "" {
+				continue
+			}
+			if err := blobs.Delete(key); err != nil {
+				zerolog.Ctx(ctx).Error().Err(err).Str("key", key).Msg("Failed to delete attachment blob")
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// returns the visitor's IP address, trusting X-Real-IP and
+// X-Forwarded-For only when the request comes from a local reverse proxy
+func ClientIP(r *http.Request) string {
+	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+
+	if ip == "127.0.0.1" {
+		if real := r.Header.Get("X-Real-IP"); real != "" {
+			return real
+		}
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			return strings.TrimSpace(strings.Split(xff, ",")[0])
+		}
+	}
+
+	return ip
+}
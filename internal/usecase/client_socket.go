@@ -0,0 +1,12 @@
+package usecase
+
+import "net/http"
+
+// header the widget sends its websocket client ID in
+const ClientSocketHeader = "X-Dumbchat-Client"
+
+// returns the websocket connection of the visitor who sent the request,
+// empty if the widget isn't connected
+func ClientSocket(r *http.Request) string {
+	return r.Header.Get(ClientSocketHeader)
+}
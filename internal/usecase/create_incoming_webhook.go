@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const incomingHookPrefix = "dch_"
+
+// generates the token of the hook URL, only its hash is stored.
+// The returned token can't be recovered later
+func CreateIncomingWebhook(db *pgxpool.Pool, hook domain.IncomingWebhook) (domain.IncomingWebhook, string, error) {
+	token := randomToken(incomingHookPrefix)
+	hook.Prefix = tokenPrefix(token, incomingHookPrefix)
+
+	var err error
+	hook.ID, err = postgres.InsertIncomingWebhook(db, hook, AuthorHash(token))
+	if err != nil {
+		return domain.IncomingWebhook{}, "", fmt.Errorf("CreateIncomingWebhook: %w", err)
+	}
+	return hook, token, nil
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// saves the webhook with a new signing secret
+func CreateWebhook(db *pgxpool.Pool, w domain.Webhook) (domain.Webhook, error) {
+	b := make([]byte, 32)
+	rand.Read(b)
+	w.Secret = hex.EncodeToString(b)
+
+	var err error
+	w.ID, err = postgres.InsertWebhook(db, w)
+	if err != nil {
+		return domain.Webhook{}, fmt.Errorf("CreateWebhook: %w", err)
+	}
+	return w, nil
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/pkg/webhook"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// deliveries sent at once
+	webhookBatch = 16
+	// claimed deliveries are retried after this time if the process dies
+	webhookLease = 2 * time.Minute
+	// the delivery is dead after this many failed attempts, about 3 hours
+	maxWebhookAttempts = 10
+	webhookRetryBase   = 30 * time.Second
+	webhookRetryMax    = time.Hour
+	// errors are cut to this length in the delivery log
+	maxWebhookErrorLen = 300
+)
+
+// sends a batch of due deliveries and schedules retries of the failed ones.
+// Returns the number of processed deliveries
+func DeliverWebhooks(ctx context.Context, db *pgxpool.Pool, sender *webhook.Sender) (int, error) {
+	deliveries, err := postgres.ClaimWebhookDeliveries(db, webhookBatch, webhookLease)
+	if err != nil {
+		return 0, fmt.Errorf("DeliverWebhooks: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, d := range deliveries {
+		wg.Go(func() {
+			d = attemptDelivery(ctx, sender, d)
+			if err := postgres.UpdateWebhookDelivery(db, d); err != nil {
+				zerolog.Ctx(ctx).Error().Err(err).Int64("delivery", d.ID).Msg("Failed to save webhook delivery")
+			}
+		})
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+func attemptDelivery(ctx context.Context, sender *webhook.Sender, d domain.WebhookDelivery) domain.WebhookDelivery {
+	status, err := sender.Send(ctx, webhook.Delivery{
+		ID:     d.ID,
+		URL:    d.URL,
+		Secret: d.Secret,
+		Event:  d.Event,
+		Body:   []byte(d.Payload),
+	})
+	d.Attempts++
+	d.LastStatus = status
+	if err == nil {
+		now := time.Now()
+		d.Status = domain.DeliveryDelivered
+		d.DeliveredAt = &now
+		d.LastError = ""
+		return d
+	}
+
+	d.LastError = err.Error()
+	if len(d.LastError) > maxWebhookErrorLen {
+		d.LastError = d.LastError[:maxWebhookErrorLen]
+	}
+	if d.Attempts >= maxWebhookAttempts {
+		d.Status = domain.DeliveryDead
+		zerolog.Ctx(ctx).Warn().Err(err).Int64("delivery", d.ID).Str("url", d.URL).Msg("Webhook delivery failed for good")
+		return d
+	}
+	d.NextAttemptAt = time.Now().Add(webhook.Backoff(d.Attempts, webhookRetryBase, webhookRetryMax))
+	return d
+}
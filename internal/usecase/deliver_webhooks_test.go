@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/pkg/webhook"
+)
+
+func TestAttemptDelivery(t *testing.T) {
+	failing := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			http.Error(w, strings.Repeat("x", 1000), http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+	sender := webhook.NewWithClient(srv.Client())
+
+	d := domain.WebhookDelivery{ID: 1, URL: srv.URL, Status: domain.DeliveryPending, Payload: `{}`}
+	for attempt := 1; attempt < maxWebhookAttempts; attempt++ {
+		before := time.Now()
+		d = attemptDelivery(context.Background(), sender, d)
+		if d.Status != domain.DeliveryPending || d.Attempts != attempt {
+			t.Fatalf("attempt %d: status %s after %d attempts", attempt, d.Status, d.Attempts)
+		}
+		if d.LastStatus != http.StatusInternalServerError || d.LastError == "" {
+			t.Errorf("attempt %d: status %d, error %q", attempt, d.LastStatus, d.LastError)
+		}
+		if wait := d.NextAttemptAt.Sub(before); wait < webhookRetryBase || wait > webhookRetryMax*11/10+time.Second {
+			t.Errorf("attempt %d: retried after %v", attempt, wait)
+		}
+	}
+
+	// the last failed attempt moves it to the dead letters
+	d = attemptDelivery(context.Background(), sender, d)
+	if d.Status != domain.DeliveryDead || d.Attempts != maxWebhookAttempts {
+		t.Fatalf("status %s after %d attempts, want dead", d.Status, d.Attempts)
+	}
+	if len(d.LastError) > maxWebhookErrorLen {
+		t.Errorf("error of %d bytes kept", len(d.LastError))
+	}
+
+	// retried by an admin
+	failing = false
+	d.Status = domain.DeliveryPending
+	d = attemptDelivery(context.Background(), sender, d)
+	if d.Status != domain.DeliveryDelivered || d.DeliveredAt == nil || d.LastError != "" {
+		t.Errorf("got %+v, want delivered", d)
+	}
+}
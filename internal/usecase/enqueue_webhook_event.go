@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// queues the event for the subscribed webhooks, the worker sends it later.
+// Returns the number of queued deliveries
+func EnqueueWebhookEvent(db *pgxpool.Pool, event string, data any) (int64, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return 0, fmt.Errorf("EnqueueWebhookEvent: %w", err)
+	}
+	now := time.Now()
+	payload, err := json.Marshal(domain.WebhookPayload{Event: event, CreatedAt: now, Data: raw})
+	if err != nil {
+		return 0, fmt.Errorf("EnqueueWebhookEvent: %w", err)
+	}
+
+	n, err := postgres.EnqueueWebhookDeliveries(db, event, string(payload), now)
+	if err != nil {
+		return 0, fmt.Errorf("EnqueueWebhookEvent: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func ExtractAnnouncementID(r *http.Request) (int64, error) {
+	id := chi.URLParam(r, "announcementID")
+	announcementID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return -1, fmt.Errorf("error extracting announcement id: %w", err)
+	}
+	return announcementID, nil
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func ExtractAPITokenID(r *http.Request) (int64, error) {
+	id := chi.URLParam(r, "tokenID")
+	tokenID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return -1, fmt.Errorf("error extracting API token id: %w", err)
+	}
+	return tokenID, nil
+}
@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func ExtractIncomingWebhookID(r *http.Request) (int64, error) {
+	id := chi.URLParam(r, "hookID")
+	hookID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return -1, fmt.Errorf("error extracting incoming webhook id: %w", err)
+	}
+	return hookID, nil
+}
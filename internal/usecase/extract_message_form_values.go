@@ -1,19 +1,44 @@
 package usecase
 
 import (
-	"github.com/acakp/dumbchat/internal/domain"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/pkg/textutil"
 )
 
-func ExtractMessageFormValues(r *http.Request) domain.Message {
+func ExtractMessageFormValues(r *http.Request, siteSecret string) domain.Message {
+	msg := NewMessage(r, r.FormValue("nickname"), r.FormValue("content"), siteSecret)
+	if replyTo, err := strconv.ParseInt(r.FormValue("reply_to"), 10, 64); err == nil {
+		msg.ReplyTo = &replyTo
+	}
+	return msg
+}
+
+// a nickname entered as "name#secret" is split into the name
+// and a tripcode derived from the secret with the site secret
+func NewMessage(r *http.Request, nickname, content, siteSecret string) domain.Message {
+	name, secret, _ := strings.Cut(nickname, "#")
 	msg := domain.Message{
-		Nickname:  r.FormValue("nickname"),
-		Content:   r.FormValue("content"),
+		Nickname:  NormalizeNickname(name),
+		Content:   textutil.Clean(content, true),
 		CreatedAt: time.Now(),
+		IP:        ClientIP(r),
+		Kind:      domain.MessageKindUser,
 	}
 	if msg.Nickname == "" {
 		msg.Nickname = "anonymous"
 	}
+	if secret != "" {
+		msg.Tripcode = Tripcode(secret, siteSecret)
+	}
 	return msg
 }
+
+// strips unsafe characters and collapses whitespace
+func NormalizeNickname(nickname string) string {
+	return strings.Join(strings.Fields(textutil.Clean(nickname, false)), " ")
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"net/http"
+	"strings"
+)
+
+const maxReportReasonLen = 500
+
+// reason comes from the htmx prompt (HX-Prompt header)
+// or from the "reason" form field
+func ExtractReportReason(r *http.Request) string {
+	reason := r.Header.Get("HX-Prompt")
+	if reason == "" {
+		reason = r.FormValue("reason")
+	}
+	reason = strings.TrimSpace(reason)
+
+	runes := []rune(reason)
+	if len(runes) > maxReportReasonLen {
+		reason = string(runes[:maxReportReasonLen])
+	}
+	return reason
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/acakp/dumbchat/config"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/pkg/imageutil"
+)
+
+const (
+	uploadField = "file"
+	// thumbnails fit into a square of this size
+	thumbnailSide = 320
+)
+
+// reads the attached file from a parsed multipart form.
+// Returns nil if nothing is attached. The file type is sniffed
+// from its contents, images are cleaned of metadata and get a thumbnail
+func ExtractUpload(r *http.Request, cfg config.Config) (*domain.Upload, error) {
+	if !hasUpload(r) {
+		return nil, nil
+	}
+	if cfg.MaxUploadBytes <= 0 {
+		return nil, domain.ErrUnsupportedFileType
+	}
+
+	header := r.MultipartForm.File[uploadField][0]
+	if header.Size > cfg.MaxUploadBytes {
+		return nil, domain.ErrFileTooLarge
+	}
+	f, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error opening upload: %w", err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, cfg.MaxUploadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading upload: %w", err)
+	}
+	if int64(len(data)) > cfg.MaxUploadBytes {
+		return nil, domain.ErrFileTooLarge
+	}
+
+	mimeType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	if !slices.Contains(cfg.UploadMIMETypes, mimeType) {
+		return nil, domain.ErrUnsupportedFileType
+	}
+
+	upload := &domain.Upload{
+		Attachment: domain.Attachment{
+			Filename:  cleanFilename(header.Filename),
+			MIME:      mimeType,
+			CreatedAt: time.Now(),
+		},
+		Data: data,
+	}
+
+	if strings.HasPrefix(mimeType, "image/") {
+		img, err := imageutil.Clean(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", domain.ErrUnsupportedFileType, err)
+		}
+		upload.Data = img.Data
+		upload.Attachment.Width = img.Img.Bounds().Dx()
+		upload.Attachment.Height = img.Img.Bounds().Dy()
+		upload.Thumb, upload.ThumbExt, err = imageutil.Thumbnail(img, thumbnailSide)
+		if err != nil {
+			return nil, err
+		}
+	}
+	upload.Attachment.Size = int64(len(upload.Data))
+
+	return upload, nil
+}
+
+// keeps only the base name without control characters
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	if len([]rune(name)) > 100 {
+		name = string([]rune(name)[:100])
+	}
+	if name == "" || name == "." || name == "/" {
+		name = "file"
+	}
+	return name
+}
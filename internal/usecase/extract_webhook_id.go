@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func ExtractWebhookID(r *http.Request) (int64, error) {
+	id := chi.URLParam(r, "webhookID")
+	webhookID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return -1, fmt.Errorf("error extracting webhook id: %w", err)
+	}
+	return webhookID, nil
+}
@@ -2,30 +2,55 @@ package usecase
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/acakp/dumbchat/config"
 	"github.com/acakp/dumbchat/internal/adapter/postgres"
 	"github.com/acakp/dumbchat/internal/domain"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func GetChatView(db *pgxpool.Pool, isAdmin bool, urls domain.URLs) (domain.ChatView, error) {
-	msgs, err := postgres.GetMessages(db)
+func GetChatView(db *pgxpool.Pool, viewer domain.Viewer, urls domain.URLs, cfg config.Config) (domain.ChatView, error) {
+	msgs, err := postgres.GetMessages(db, viewer.IsAdmin)
 	if err != nil {
 		return domain.ChatView{}, fmt.Errorf("GetChatView: %w", err)
 	}
 
+	view, err := newChatView(db, msgs, viewer, urls, cfg)
+	if err != nil {
+		return domain.ChatView{}, fmt.Errorf("GetChatView: %w", err)
+	}
+	return view, nil
+}
+
+// renders messages together with everything else shown on the chat page
+func newChatView(db *pgxpool.Pool, msgs []domain.Message, viewer domain.Viewer, urls domain.URLs, cfg config.Config) (domain.ChatView, error) {
 	views := make([]domain.MessageView, 0, len(msgs))
 	for _, msg := range msgs {
-		views = append(views, domain.MessageView{
-			URLs:    urls,
-			Msg:     msg,
-			IsAdmin: isAdmin,
-		})
+		views = append(views, NewMessageView(msg, viewer, urls, cfg))
+	}
+	if err := AttachMessageDetails(db, views, viewer.ReactorID); err != nil {
+		return domain.ChatView{}, err
+	}
+
+	pinned, err := GetPinnedView(db, viewer.IsAdmin, urls)
+	if err != nil {
+		return domain.ChatView{}, err
+	}
+
+	var accept string
+	if cfg.MaxUploadBytes > 0 {
+		accept = strings.Join(cfg.UploadMIMETypes, ",")
 	}
 
 	return domain.ChatView{
-		Messages: views,
-		IsAdmin:  isAdmin,
-		URLs:     urls,
+		Messages:     views,
+		IsAdmin:      viewer.IsAdmin,
+		Pinned:       pinned,
+		UploadAccept: accept,
+		URLs:         urls,
+
+		MaxMessageLength:  cfg.MaxMessageLength,
+		MaxNicknameLength: cfg.MaxNicknameLength,
 	}, nil
 }
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/acakp/dumbchat/config"
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// chat page centred on one message with cfg.PermalinkContext
+// messages before and after it. Hidden messages are only shown to admins
+func GetContextView(db *pgxpool.Pool, messageID int, viewer domain.Viewer, urls domain.URLs, cfg config.Config) (domain.ChatView, error) {
+	msg, err := postgres.GetMessage(db, messageID)
+	if err != nil {
+		return domain.ChatView{}, fmt.Errorf("GetContextView: %w", err)
+	}
+	if msg.Hidden && !viewer.IsAdmin {
+		return domain.ChatView{}, domain.ErrMessageNotFound
+	}
+
+	msgs, err := postgres.GetMessagesAround(db, msg.ID, cfg.PermalinkContext, viewer.IsAdmin)
+	if err != nil {
+		return domain.ChatView{}, fmt.Errorf("GetContextView: %w", err)
+	}
+
+	view, err := newChatView(db, msgs, viewer, urls, cfg)
+	if err != nil {
+		return domain.ChatView{}, fmt.Errorf("GetContextView: %w", err)
+	}
+	view.FocusID = msg.ID
+	return view, nil
+}
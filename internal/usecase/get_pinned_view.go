@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func GetPinnedView(db *pgxpool.Pool, isAdmin bool, urls domain.URLs) (domain.PinnedView, error) {
+	msgs, err := postgres.GetPinnedMessages(db)
+	if err != nil {
+		return domain.PinnedView{}, fmt.Errorf("GetPinnedView: %w", err)
+	}
+	announcements, err := postgres.GetAnnouncements(db)
+	if err != nil {
+		return domain.PinnedView{}, fmt.Errorf("GetPinnedView: %w", err)
+	}
+
+	return domain.PinnedView{
+		Messages:      msgs,
+		Announcements: announcements,
+		IsAdmin:       isAdmin,
+		URLs:          urls,
+	}, nil
+}
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func GetViewer(db *pgxpool.Pool, r *http.Request, secret string) domain.Viewer {
+	viewer := domain.Viewer{
+		IsAdmin: IsAdmin(db, r),
+	}
+	if token, ok := AuthorToken(r, secret); ok {
+		viewer.AuthorHash = AuthorHash(token)
+		viewer.ReactorID = viewer.AuthorHash
+	} else {
+		viewer.ReactorID = AuthorHash("ip:" + ClientIP(r))
+	}
+	return viewer
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"net/http"
+)
+
+// proves that the browser is signed in to a registered nickname
+const identityCookie = "identity"
+
+// returns the hash of the visitor's identity token, empty if there is none
+func IdentityHash(r *http.Request, secret string) string {
+	token, ok := readSignedToken(r, identityCookie, secret)
+	if !ok {
+		return ""
+	}
+	return AuthorHash(token)
+}
+
+// reuses the visitor's identity token or issues a new one and returns its hash
+func IssueIdentityToken(w http.ResponseWriter, r *http.Request, secret string) string {
+	if hash := IdentityHash(r, secret); hash != "" {
+		return hash
+	}
+	return AuthorHash(issueSignedToken(w, identityCookie, secret))
+}
+
+func ClearIdentityToken(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     identityCookie,
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   -1,
+	})
+}
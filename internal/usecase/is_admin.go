@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// requests authenticated with an API token are admin ones
+// if the token has the moderate scope
+func IsAdmin(db *pgxpool.Pool, r *http.Request) bool {
+	if t, ok := APITokenFrom(r.Context()); ok {
+		return t.HasScope(domain.ScopeModerate)
+	}
+	c, err := r.Cookie("admin_session")
+	if err != nil {
+		return false
+	}
+	return postgres.IsAdminSession(db, c) == nil
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func ListMessages(db *pgxpool.Pool, q domain.MessagePageQuery, viewer domain.Viewer, urls domain.URLs) (domain.APIMessagePage, error) {
+	// one extra row tells whether there are more messages
+	limit := q.Limit
+	q.Limit++
+	msgs, err := postgres.ListMessages(db, q, viewer.IsAdmin)
+	if err != nil {
+		return domain.APIMessagePage{}, fmt.Errorf("ListMessages: %w", err)
+	}
+
+	var page domain.APIMessagePage
+	if len(msgs) > limit {
+		page.HasMore = true
+		// the extra row is the one farthest from the cursor
+		if q.After > 0 {
+			msgs = msgs[:limit]
+		} else {
+			msgs = msgs[1:]
+		}
+	}
+
+	page.Messages, err = NewAPIMessages(db, msgs, viewer, urls)
+	if err != nil {
+		return domain.APIMessagePage{}, fmt.Errorf("ListMessages: %w", err)
+	}
+	return page, nil
+}
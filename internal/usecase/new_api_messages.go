@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// converts messages to their API form with reactions and attachments
+func NewAPIMessages(db *pgxpool.Pool, msgs []domain.Message, viewer domain.Viewer, urls domain.URLs) ([]domain.APIMessage, error) {
+	views := make([]domain.MessageView, 0, len(msgs))
+	for _, msg := range msgs {
+		// content is returned as is, no need to render it
+		views = append(views, domain.MessageView{Msg: msg})
+	}
+	if err := AttachReactions(db, views, viewer.ReactorID); err != nil {
+		return nil, err
+	}
+	if err := AttachFiles(db, views); err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.APIMessage, 0, len(views))
+	for _, v := range views {
+		m := domain.APIMessage{
+			Message:     v.Msg,
+			Reactions:   v.Reactions,
+			Attachments: make([]domain.APIAttachment, 0, len(v.Attachments)),
+			URL:         urls.Permalink(v.Msg.ID),
+		}
+		if m.Reactions == nil {
+			m.Reactions = []domain.ReactionCount{}
+		}
+		for _, a := range v.Attachments {
+			att := domain.APIAttachment{
+				Filename: a.Filename,
+				MIME:     a.MIME,
+				Size:     a.Size,
+				Width:    a.Width,
+				Height:   a.Height,
+				URL:      urls.Attachment(a.Key),
+			}
+			if a.IsImage() {
+				att.ThumbnailURL = urls.Attachment(a.ThumbKey)
+			}
+			m.Attachments = append(m.Attachments, att)
+		}
+		res = append(res, m)
+	}
+	return res, nil
+}
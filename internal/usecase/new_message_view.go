@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/acakp/dumbchat/config"
+	"github.com/acakp/dumbchat/internal/domain"
+)
+
+func NewMessageView(msg domain.Message, viewer domain.Viewer, urls domain.URLs, cfg config.Config) domain.MessageView {
+	isAuthor := viewer.IsAuthorOf(msg)
+	if msg.ReplyPreview.Hidden && !viewer.IsAdmin {
+		msg.ReplyPreview = domain.MessagePreview{Hidden: true}
+	}
+	return domain.MessageView{
+		URLs:      urls,
+		Msg:       msg,
+		Body:      RenderContent(msg.Content, cfg.Markdown),
+		IsAdmin:   viewer.IsAdmin,
+		IsAuthor:  isAuthor,
+		CanEdit:   CanEditMessage(msg, viewer, cfg.EditWindow),
+		MaxLength: cfg.MaxMessageLength,
+	}
+}
+
+// admins can edit any message, authors only their own
+// within the edit window
+func CanEditMessage(msg domain.Message, viewer domain.Viewer, window time.Duration) bool {
+	if viewer.IsAdmin {
+		return true
+	}
+	return viewer.IsAuthorOf(msg) && time.Since(msg.CreatedAt) <= window
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// returns t as read back from a timestamptz column
+func roundTrip(t *testing.T, v time.Time) time.Time {
+	t.Helper()
+	m := pgtype.NewMap()
+	buf, err := m.Encode(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, v, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got time.Time
+	if err = m.Scan(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, buf, &got); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestCanEditMessageOutsideUTC(t *testing.T) {
+	for _, zone := range []*time.Location{time.FixedZone("UTC+9", 9*60*60), time.FixedZone("UTC-5", -5*60*60)} {
+		local := time.Local
+		time.Local = zone
+
+		author := domain.Viewer{AuthorHash: "a"}
+		tests := []struct {
+			name   string
+			age    time.Duration
+			viewer domain.Viewer
+			want   bool
+		}{
+			{"fresh", time.Minute, author, true},
+			{"expired", 20 * time.Minute, author, false},
+			{"other visitor", time.Minute, domain.Viewer{AuthorHash: "b"}, false},
+			{"admin", 24 * time.Hour, domain.Viewer{IsAdmin: true}, true},
+		}
+		for _, tt := range tests {
+			msg := domain.Message{AuthorHash: "a", CreatedAt: roundTrip(t, time.Now().Add(-tt.age))}
+			if got := CanEditMessage(msg, tt.viewer, 15*time.Minute); got != tt.want {
+				t.Errorf("%s, %s: CanEditMessage = %v, want %v", zone, tt.name, got, tt.want)
+			}
+		}
+		time.Local = local
+	}
+}
+
+func TestParseFormTimeInServerZone(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC+9", 9*60*60)
+	defer func() { time.Local = local }()
+
+	got, err := ParseFormTime("2026-01-02T15:04")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 1, 2, 6, 4, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("ParseFormTime = %v, want %v", got, want)
+	}
+}
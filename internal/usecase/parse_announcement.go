@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+)
+
+// expires_in is an optional lifetime in minutes
+func ParseAnnouncement(r *http.Request) (domain.Announcement, error) {
+	err := r.ParseForm()
+	if err != nil {
+		return domain.Announcement{}, fmt.Errorf("error parsing form: %w", err)
+	}
+
+	a := domain.Announcement{
+		Content:   strings.TrimSpace(r.FormValue("content")),
+		CreatedAt: time.Now(),
+	}
+	if a.Content == "" {
+		return domain.Announcement{}, fmt.Errorf("content field is required")
+	}
+
+	if expiresIn := r.FormValue("expires_in"); expiresIn != "" {
+		minutes, err := strconv.Atoi(expiresIn)
+		if err != nil || minutes <= 0 {
+			return domain.Announcement{}, fmt.Errorf("expires_in must be a positive number of minutes")
+		}
+		expiresAt := a.CreatedAt.Add(time.Duration(minutes) * time.Minute)
+		a.ExpiresAt = &expiresAt
+	}
+	return a, nil
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/domain"
+)
+
+// accepts domain.APINewMessage as JSON, or the same form
+// as the chat page when a file is attached
+func ParseAPIMessage(r *http.Request, siteSecret string) (domain.Message, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return ParseMessage(r, siteSecret)
+	}
+
+	var body domain.APINewMessage
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&body); err != nil {
+		return domain.Message{}, fmt.Errorf("invalid JSON body: %w", err)
+	}
+
+	msg := NewMessage(r, body.Nickname, body.Content, siteSecret)
+	msg.ReplyTo = body.ReplyTo
+	if msg.Content == "" {
+		return domain.Message{}, errors.New("content field is required")
+	}
+	return msg, nil
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+)
+
+const (
+	defaultTokenRateLimit = 60
+	maxTokenRateLimit     = 6000
+)
+
+// rate_limit is in requests per minute
+func ParseAPIToken(r *http.Request) (domain.APIToken, error) {
+	err := r.ParseForm()
+	if err != nil {
+		return domain.APIToken{}, fmt.Errorf("error parsing form: %w", err)
+	}
+
+	t := domain.APIToken{
+		Name:      strings.TrimSpace(r.FormValue("name")),
+		RateLimit: defaultTokenRateLimit,
+		CreatedAt: time.Now(),
+	}
+	if t.Name == "" {
+		return domain.APIToken{}, errors.New("name field is required")
+	}
+	for _, scope := range r.Form["scopes"] {
+		if !domain.IsAPIScope(scope) {
+			return domain.APIToken{}, fmt.Errorf("unknown scope %q", scope)
+		}
+		if !t.HasScope(scope) {
+			t.Scopes = append(t.Scopes, scope)
+		}
+	}
+	if len(t.Scopes) == 0 {
+		return domain.APIToken{}, errors.New("at least one scope is required")
+	}
+	if v := r.FormValue("rate_limit"); v != "" {
+		t.RateLimit, err = strconv.Atoi(v)
+		if err != nil || t.RateLimit < 1 || t.RateLimit > maxTokenRateLimit {
+			return domain.APIToken{}, fmt.Errorf("rate_limit must be between 1 and %d requests per minute", maxTokenRateLimit)
+		}
+	}
+	return t, nil
+}
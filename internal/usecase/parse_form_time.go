@@ -0,0 +1,12 @@
+package usecase
+
+import "time"
+
+// layout of <input type="datetime-local">
+const datetimeLocalLayout = "2006-01-02T15:04"
+
+// parses a datetime-local form value. The value has no zone, it's taken
+// in the server zone that message times are shown in
+func ParseFormTime(value string) (time.Time, error) {
+	return time.ParseInLocation(datetimeLocalLayout, value, time.Local)
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+)
+
+func ParseIncomingWebhook(r *http.Request, maxNameLen int) (domain.IncomingWebhook, error) {
+	err := r.ParseForm()
+	if err != nil {
+		return domain.IncomingWebhook{}, fmt.Errorf("error parsing form: %w", err)
+	}
+
+	hook := domain.IncomingWebhook{
+		Name:      NormalizeNickname(r.FormValue("name")),
+		CreatedAt: time.Now(),
+	}
+	if hook.Name == "" {
+		return domain.IncomingWebhook{}, errors.New("name field is required")
+	}
+	if err = ValidateNicknameFormat(hook.Name, maxNameLen); err != nil {
+		return domain.IncomingWebhook{}, err
+	}
+	return hook, nil
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/pkg/textutil"
+)
+
+// turns domain.IncomingWebhookPayload into a bot message
+// posted under the hook's name unless the payload sets one
+func ParseIncomingWebhookPayload(r *http.Request, hook domain.IncomingWebhook) (domain.Message, error) {
+	var body domain.IncomingWebhookPayload
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return domain.Message{}, fmt.Errorf("invalid JSON body: %w", err)
+	}
+
+	msg := domain.Message{
+		Nickname:  NormalizeNickname(body.Name),
+		Content:   textutil.Clean(body.Text, true),
+		CreatedAt: time.Now(),
+		Kind:      domain.MessageKindBot,
+	}
+	if msg.Nickname == "" {
+		msg.Nickname = hook.Name
+	}
+	if msg.Content == "" {
+		return domain.Message{}, errors.New("text field is required")
+	}
+	return msg, nil
+}
@@ -1,21 +1,30 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/acakp/dumbchat/internal/domain"
 )
 
-func ParseMessage(r *http.Request) (domain.Message, error) {
-	err := r.ParseForm()
-	if err != nil {
+// file parts bigger than this are buffered on disk by net/http
+const multipartMemory = 8 << 20
+
+func ParseMessage(r *http.Request, siteSecret string) (domain.Message, error) {
+	err := r.ParseMultipartForm(multipartMemory)
+	if err != nil && !errors.Is(err, http.ErrNotMultipart) {
 		return domain.Message{}, fmt.Errorf("error parsing form: %w", err)
 	}
 
-	msg := ExtractMessageFormValues(r)
-	if msg.Content == "" {
-		return domain.Message{}, fmt.Errorf("content field is required: %w", err)
+	msg := ExtractMessageFormValues(r, siteSecret)
+	// a message may consist of an attachment only
+	if msg.Content == "" && !hasUpload(r) {
+		return domain.Message{}, errors.New("content field is required")
 	}
 	return msg, nil
 }
+
+func hasUpload(r *http.Request) bool {
+	return r.MultipartForm != nil && len(r.MultipartForm.File[uploadField]) > 0
+}
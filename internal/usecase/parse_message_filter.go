@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/acakp/dumbchat/internal/domain"
+)
+
+func ParseMessageFilter(r *http.Request) (domain.MessageFilter, error) {
+	err := r.ParseForm()
+	if err != nil {
+		return domain.MessageFilter{}, fmt.Errorf("error parsing form: %w", err)
+	}
+
+	filter := domain.MessageFilter{
+		Nickname: strings.TrimSpace(r.FormValue("nickname")),
+		IP:       strings.TrimSpace(r.FormValue("ip")),
+		Pattern:  r.FormValue("pattern"),
+	}
+
+	if from := r.FormValue("from"); from != "" {
+		filter.From, err = ParseFormTime(from)
+		if err != nil {
+			return domain.MessageFilter{}, fmt.Errorf("invalid 'from' time: %w", err)
+		}
+	}
+	if to := r.FormValue("to"); to != "" {
+		filter.To, err = ParseFormTime(to)
+		if err != nil {
+			return domain.MessageFilter{}, fmt.Errorf("invalid 'to' time: %w", err)
+		}
+	}
+	if filter.Pattern != "" {
+		// postgres regexps are close enough to RE2 to catch typos early,
+		// the rest is reported as domain.ErrInvalidPattern by the query
+		if _, err = regexp.Compile(filter.Pattern); err != nil {
+			return domain.MessageFilter{}, fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+
+	if filter.IsEmpty() {
+		return domain.MessageFilter{}, domain.ErrEmptyFilter
+	}
+	return filter, nil
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// reads the query string of the API message list,
+// times are expected in RFC 3339
+func ParseMessagePageQuery(r *http.Request) (domain.MessagePageQuery, error) {
+	values := r.URL.Query()
+	q := domain.MessagePageQuery{
+		Limit:    defaultPageLimit,
+		Nickname: strings.TrimSpace(values.Get("nickname")),
+	}
+
+	var err error
+	for name, dst := range map[string]*int64{"before": &q.Before, "after": &q.After} {
+		if v := values.Get(name); v != "" {
+			*dst, err = strconv.ParseInt(v, 10, 64)
+			if err != nil || *dst <= 0 {
+				return domain.MessagePageQuery{}, fmt.Errorf("'%s' must be a message ID", name)
+			}
+		}
+	}
+	if q.Before > 0 && q.After > 0 {
+		return domain.MessagePageQuery{}, errors.New("'before' and 'after' can't be used together")
+	}
+	if v := values.Get("limit"); v != "" {
+		q.Limit, err = strconv.Atoi(v)
+		if err != nil || q.Limit < 1 || q.Limit > maxPageLimit {
+			return domain.MessagePageQuery{}, fmt.Errorf("'limit' must be between 1 and %d", maxPageLimit)
+		}
+	}
+	for name, dst := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		if v := values.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return domain.MessagePageQuery{}, fmt.Errorf("'%s' must be an RFC 3339 time", name)
+			}
+			*dst = t
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/acakp/dumbchat/internal/domain"
+)
+
+func ParseSearchQuery(r *http.Request) (domain.SearchQuery, error) {
+	q := domain.SearchQuery{
+		Text:     strings.TrimSpace(r.FormValue("q")),
+		Nickname: strings.TrimSpace(r.FormValue("nickname")),
+	}
+
+	var err error
+	if from := r.FormValue("from"); from != "" {
+		q.From, err = ParseFormTime(from)
+		if err != nil {
+			return domain.SearchQuery{}, fmt.Errorf("invalid 'from' time: %w", err)
+		}
+	}
+	if to := r.FormValue("to"); to != "" {
+		q.To, err = ParseFormTime(to)
+		if err != nil {
+			return domain.SearchQuery{}, fmt.Errorf("invalid 'to' time: %w", err)
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/domain"
+)
+
+// webhooks are set up by admins, so unlike link previews
+// they may point to private addresses, e.g. a local receiver
+func ParseWebhook(r *http.Request) (domain.Webhook, error) {
+	err := r.ParseForm()
+	if err != nil {
+		return domain.Webhook{}, fmt.Errorf("error parsing form: %w", err)
+	}
+
+	w := domain.Webhook{
+		URL:       strings.TrimSpace(r.FormValue("url")),
+		CreatedAt: time.Now(),
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.Webhook{}, errors.New("url must be an http or https URL")
+	}
+	for _, event := range r.Form["events"] {
+		if !domain.IsWebhookEvent(event) {
+			return domain.Webhook{}, fmt.Errorf("unknown event %q", event)
+		}
+		if !w.Subscribes(event) {
+			w.Events = append(w.Events, event)
+		}
+	}
+	if len(w.Events) == 0 {
+		return domain.Webhook{}, errors.New("at least one event is required")
+	}
+	return w, nil
+}
@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// returns 32 random bytes in hex after the prefix
+// that marks the kind of token in logs and secret scanners
+func randomToken(prefix string) string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+// beginning of the token shown to tell tokens apart
+func tokenPrefix(token, prefix string) string {
+	return token[:len(prefix)+6]
+}
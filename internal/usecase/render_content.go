@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"html/template"
+
+	"github.com/acakp/dumbchat/pkg/markdown"
+)
+
+// renders message content as HTML, with Markdown or as plain text
+func RenderContent(content string, withMarkdown bool) template.HTML {
+	opts := markdown.Options{Mentions: true}
+	if withMarkdown {
+		return template.HTML(markdown.Render(content, opts))
+	}
+	return template.HTML(markdown.Plain(content, opts))
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const searchLimit = 50
+
+var markReplacer = strings.NewReplacer(
+	domain.SearchMarkStart, "<mark>",
+	domain.SearchMarkStop, "</mark>",
+)
+
+func SearchMessages(db *pgxpool.Pool, q domain.SearchQuery, isAdmin bool, urls domain.URLs) (domain.SearchView, error) {
+	view := domain.SearchView{Query: q, URLs: urls}
+	if q.IsEmpty() {
+		return view, nil
+	}
+
+	// one extra row tells whether there are more results
+	results, err := postgres.SearchMessages(db, q, isAdmin, searchLimit+1)
+	if err != nil {
+		return domain.SearchView{}, fmt.Errorf("SearchMessages: %w", err)
+	}
+	if len(results) > searchLimit {
+		results = results[:searchLimit]
+		view.Truncated = true
+	}
+	for i := range results {
+		results[i].Headline = template.HTML(markReplacer.Replace(html.EscapeString(results[i].Fragment)))
+	}
+	view.Results = results
+	return view, nil
+}
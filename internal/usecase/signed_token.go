@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// returns the token from a cookie holding "token.signature"
+// if the signature is valid
+func readSignedToken(r *http.Request, name, secret string) (string, bool) {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return "", false
+	}
+	token, sig, ok := strings.Cut(c.Value, ".")
+	if !ok || token == "" {
+		return "", false
+	}
+	if !hmac.Equal([]byte(sig), []byte(signToken(token, secret))) {
+		return "", false
+	}
+	return token, true
+}
+
+// sets a cookie with a new random signed token valid for a year
+func issueSignedToken(w http.ResponseWriter, name, secret string) string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	token := hex.EncodeToString(b)
+
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    token + "." + signToken(token, secret),
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   365 * 24 * 60 * 60, // 1 year
+	}
+	http.SetCookie(w, cookie)
+	return token
+}
+
+// signs cookie tokens, so they can't be forged without the site secret
+func signToken(token, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// returns the nickname a new websocket connection gets targeted events for:
+// the registered nickname the browser is signed in to, else the one
+// it last posted under. Bots with a post token may pick one with ?nickname=.
+// Nicknames registered by someone else are never returned
+func SocketNickname(db *pgxpool.Pool, r *http.Request, secret string) (string, error) {
+	if identity := IdentityHash(r, secret); identity != "" {
+		nickname, err := postgres.GetSessionNickname(db, identity)
+		if err == nil {
+			return nickname, nil
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return "", fmt.Errorf("SocketNickname: %w", err)
+		}
+	}
+
+	var nickname string
+	if t, ok := APITokenFrom(r.Context()); ok && t.HasScope(domain.ScopePost) {
+		nickname = NormalizeNickname(r.URL.Query().Get("nickname"))
+	} else if token, ok := AuthorToken(r, secret); ok {
+		var err error
+		nickname, err = postgres.GetAuthorNickname(db, AuthorHash(token))
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return "", fmt.Errorf("SocketNickname: %w", err)
+		}
+	}
+	if nickname == "" {
+		return "", nil
+	}
+
+	_, err := CheckNicknameOwner(db, r, nickname, secret)
+	if errors.Is(err, domain.ErrNicknameReserved) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("SocketNickname: %w", err)
+	}
+	return nickname, nil
+}
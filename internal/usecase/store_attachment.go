@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// puts the upload into the blob store and saves it for the message
+func StoreAttachment(db *pgxpool.Pool, blobs domain.BlobStore, messageID int64, upload domain.Upload) (domain.Attachment, error) {
+	a := upload.Attachment
+	a.MessageID = messageID
+	a.Key = newBlobKey(extensionFor(a.MIME))
+
+	if err := blobs.Put(a.Key, bytes.NewReader(upload.Data)); err != nil {
+		return domain.Attachment{}, fmt.Errorf("StoreAttachment: %w", err)
+	}
+	if upload.Thumb != nil {
+		a.ThumbKey = newBlobKey(upload.ThumbExt)
+		if err := blobs.Put(a.ThumbKey, bytes.NewReader(upload.Thumb)); err != nil {
+			blobs.Delete(a.Key)
+			return domain.Attachment{}, fmt.Errorf("StoreAttachment: %w", err)
+		}
+	}
+
+	id, err := postgres.InsertAttachment(db, a)
+	if err != nil {
+		blobs.Delete(a.Key)
+		if a.ThumbKey != "" {
+			blobs.Delete(a.ThumbKey)
+		}
+		return domain.Attachment{}, fmt.Errorf("StoreAttachment: %w", err)
+	}
+	a.ID = id
+	return a, nil
+}
+
+// random key, so files of other visitors can't be guessed
+func newBlobKey(ext string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b) + ext
+}
+
+func extensionFor(mimeType string) string {
+	switch mimeType {
+	case "image/jpeg":
+		return ".jpg"
+	case "text/plain":
+		return ".txt"
+	}
+	exts, _ := mime.ExtensionsByType(mimeType)
+	if len(exts) == 0 {
+		return ""
+	}
+	return exts[0]
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// tripcodes are 10 characters of a keyed hash of the secret,
+// enough to tell posters apart without revealing anything about the secret
+const tripcodeLength = 10
+
+// derives a tripcode from the secret part of "name#secret".
+// The site secret keys the hash, so tripcodes can't be brute-forced offline
+// or compared with other sites
+func Tripcode(secret, siteSecret string) string {
+	mac := hmac.New(sha256.New, []byte(siteSecret))
+	mac.Write([]byte("tripcode:" + secret))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))[:tripcodeLength]
+}
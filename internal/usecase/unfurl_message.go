@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/acakp/dumbchat/internal/adapter/postgres"
+	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/pkg/markdown"
+	"github.com/acakp/dumbchat/pkg/unfurl"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// cached previews (and failures) are refetched after this time
+	previewTTL     = 24 * time.Hour
+	previewTimeout = 10 * time.Second
+)
+
+var previewImageExts = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// fetches and caches a preview of the first link in the message, its image
+// is downloaded into the blob store. Failed image downloads are logged to the ctx logger.
+// Returns false if the message has no link or the page has no usable metadata
+func UnfurlMessage(ctx context.Context, db *pgxpool.Pool, blobs domain.BlobStore, u *unfurl.Unfurler, msg domain.Message) (domain.LinkPreview, bool, error) {
+	links := markdown.Links(msg.Content)
+	if len(links) == 0 {
+		return domain.LinkPreview{}, false, nil
+	}
+	url := links[0]
+
+	cached, err := postgres.GetLinkPreviews(db, []string{url})
+	if err != nil {
+		return domain.LinkPreview{}, false, fmt.Errorf("UnfurlMessage: %w", err)
+	}
+	if p, ok := cached[url]; ok && time.Since(p.FetchedAt) < previewTTL {
+		return p, !p.Failed, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, previewTimeout)
+	defer cancel()
+	fetched, fetchErr := u.Fetch(ctx, url)
+
+	p := domain.LinkPreview{
+		URL:         url,
+		Title:       fetched.Title,
+		Description: fetched.Description,
+		ImageURL:    fetched.Image,
+		SiteName:    fetched.SiteName,
+		FetchedAt:   time.Now(),
+		Failed:      fetchErr != nil || fetched.IsEmpty(),
+	}
+	if !p.Failed && p.ImageURL != "" {
+		p.ImageKey = storePreviewImage(ctx, blobs, u, p.ImageURL)
+	}
+	if err = postgres.SaveLinkPreview(db, p); err != nil {
+		return domain.LinkPreview{}, false, fmt.Errorf("UnfurlMessage: %w", err)
+	}
+	return p, !p.Failed, nil
+}
+
+// downloads the image under a key derived from its URL, so refetching
+// a preview replaces the old copy. Returns an empty key if it failed
+func storePreviewImage(ctx context.Context, blobs domain.BlobStore, u *unfurl.Unfurler, imageURL string) string {
+	data, contentType, err := u.FetchImage(ctx, imageURL)
+	if err != nil {
+		zerolog.Ctx(ctx).Debug().Err(err).Str("url", imageURL).Msg("Skipping preview image")
+		return ""
+	}
+	sum := sha256.Sum256([]byte(imageURL))
+	key := "preview-" + hex.EncodeToString(sum[:16]) + previewImageExts[contentType]
+	if err := blobs.Put(key, bytes.NewReader(data)); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("url", imageURL).Msg("Failed to store preview image")
+		return ""
+	}
+	return key
+}
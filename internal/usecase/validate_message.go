@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/acakp/dumbchat/pkg/textutil"
+)
+
+var nicknameRe = regexp.MustCompile(`^[\p{L}\p{M}\p{N}_.\- ]+$`)
+
+// errors are meant to be shown to the visitor as is
+func ValidateContent(content string, maxLen int) error {
+	if n := textutil.Length(content); n > maxLen {
+		return fmt.Errorf("Message is too long: %d characters, the limit is %d", n, maxLen)
+	}
+	return nil
+}
+
+// length and charset rules apply to everyone, admins included
+func ValidateNicknameFormat(nickname string, maxLen int) error {
+	if n := textutil.Length(nickname); n > maxLen {
+		return fmt.Errorf("Nickname is too long: %d characters, the limit is %d", n, maxLen)
+	}
+	if !nicknameRe.MatchString(nickname) {
+		return fmt.Errorf("Nickname may only contain letters, digits, spaces and _ . -")
+	}
+	// look-alike letters of other scripts could impersonate registered nicknames
+	if !textutil.SingleScript(nickname) {
+		return fmt.Errorf("Nickname may not mix alphabets, like Latin and Cyrillic letters")
+	}
+	return nil
+}
@@ -5,14 +5,17 @@ import (
 	"strings"
 
 	"github.com/acakp/dumbchat/internal/domain"
+	"github.com/acakp/dumbchat/pkg/textutil"
 )
 
 func ValidateNickname(msg domain.Message, bannedNicknames []string) error {
 	if len(bannedNicknames) == 0 {
 		return nil
 	}
+	// compared by skeleton, so "ADMIN" or "аdmin" with a Cyrillic "а" match "admin"
+	nickname := textutil.Skeleton(msg.Nickname)
 	for _, banned := range bannedNicknames {
-		if strings.Contains(msg.Nickname, banned) {
+		if banned != "" && strings.Contains(nickname, textutil.Skeleton(banned)) {
 			return fmt.Errorf("prohibited nickname")
 		}
 	}
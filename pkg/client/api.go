@@ -0,0 +1,182 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const apiPrefix = "/api/v1"
+
+// CommandResult is the outcome of a slash command:
+// the message it posted, or its reply when it posted nothing
+type CommandResult struct {
+	Message *Message
+	Reply   *CommandReply
+}
+
+// Send posts a message. Content is always posted as is,
+// a leading "/" is escaped so it can't run a command, see Command
+func (c *Client) Send(ctx context.Context, m NewMessage) (Message, error) {
+	if strings.HasPrefix(strings.TrimLeft(m.Content, " \t\r\n"), "/") {
+		m.Content = "/" + strings.TrimLeft(m.Content, " \t\r\n")
+	}
+	var posted Message
+	_, err := c.post(ctx, m, &posted, nil)
+	return posted, err
+}
+
+// Reply posts a reply to the message
+func (c *Client) Reply(ctx context.Context, to Message, content string) (Message, error) {
+	return c.Send(ctx, NewMessage{Content: content, ReplyTo: &to.ID})
+}
+
+// Command runs a slash command like "/me waves". The reply
+// also arrives as a command_reply event while connected
+func (c *Client) Command(ctx context.Context, line string) (CommandResult, error) {
+	var posted Message
+	var reply CommandReply
+	status, err := c.post(ctx, NewMessage{Content: line}, &posted, &reply)
+	if err != nil {
+		return CommandResult{}, err
+	}
+	if status == http.StatusCreated {
+		return CommandResult{Message: &posted}, nil
+	}
+	return CommandResult{Reply: &reply}, nil
+}
+
+// posts a message, a 201 response is decoded into posted and 200 into reply
+func (c *Client) post(ctx context.Context, m NewMessage, posted *Message, reply *CommandReply) (int, error) {
+	if m.Nickname == "" {
+		m.Nickname = c.nickname
+	}
+	body, err := json.Marshal(m)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.do(ctx, http.MethodPost, "/messages", nil, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var out any = posted
+	if resp.StatusCode == http.StatusOK {
+		if reply == nil {
+			return resp.StatusCode, fmt.Errorf("dumbchat: message wasn't posted")
+		}
+		out = reply
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp.StatusCode, fmt.Errorf("error decoding response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// Get returns a message by ID
+func (c *Client) Get(ctx context.Context, id int64) (Message, error) {
+	var m Message
+	err := c.getJSON(ctx, "/messages/"+strconv.FormatInt(id, 10), nil, &m)
+	return m, err
+}
+
+// Messages returns a page of messages, oldest first
+func (c *Client) Messages(ctx context.Context, q MessageQuery) (MessagePage, error) {
+	v := url.Values{}
+	if q.Before > 0 {
+		v.Set("before", strconv.FormatInt(q.Before, 10))
+	}
+	if q.After > 0 {
+		v.Set("after", strconv.FormatInt(q.After, 10))
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Nickname != "" {
+		v.Set("nickname", q.Nickname)
+	}
+	if !q.From.IsZero() {
+		v.Set("from", q.From.Format(time.RFC3339))
+	}
+	if !q.To.IsZero() {
+		v.Set("to", q.To.Format(time.RFC3339))
+	}
+	var page MessagePage
+	err := c.getJSON(ctx, "/messages", v, &page)
+	return page, err
+}
+
+// Delete deletes a message, the token needs the moderate scope
+func (c *Client) Delete(ctx context.Context, id int64) error {
+	resp, err := c.do(ctx, http.MethodDelete, "/messages/"+strconv.FormatInt(id, 10), nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	resp, err := c.do(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
+}
+
+// sends an API request, error responses are returned as *APIError
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Response, error) {
+	u := *c.base
+	u.Path += apiPrefix + path
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = c.header()
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.mu.Lock()
+	if c.socketID != "" {
+		// command replies are also sent to this connection
+		req.Header.Set("X-Dumbchat-Client", c.socketID)
+	}
+	c.mu.Unlock()
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, decodeError(resp)
+	}
+	return resp, nil
+}
+
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error APIError `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if json.Unmarshal(data, &body) != nil || body.Error.Message == "" {
+		body.Error.Message = http.StatusText(resp.StatusCode)
+	}
+	body.Error.Status = resp.StatusCode
+	return &body.Error
+}
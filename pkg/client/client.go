@@ -0,0 +1,342 @@
+// Package client is a Go client for dumbchat bots and integrations.
+// It follows the chat over the websocket, decodes its events for the
+// registered handlers and posts through the JSON API. Lost connections
+// are re-established, and messages posted in between are fetched from
+// the API, so handlers don't miss any:
+//
+//	c, err := client.New("https://example.com/chat", token, client.WithNickname("bot"))
+//	c.OnMessage(func(ctx context.Context, m client.Message) { ... })
+//	err = c.Run(ctx)
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// the server pings every 30 seconds
+	readTimeout  = 70 * time.Second
+	writeTimeout = 10 * time.Second
+	// page size of the resume requests
+	resumePage = 200
+)
+
+// HandlerFunc handles an event. Handlers run one at a time in the order
+// events arrive, a slow handler delays the following events
+type HandlerFunc func(ctx context.Context, e Event)
+
+type Client struct {
+	base     *url.URL
+	token    string
+	nickname string
+	http     *http.Client
+	dialer   *websocket.Dialer
+
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu           sync.Mutex
+	handlers     map[string][]HandlerFunc
+	onConnect    []func(ctx context.Context)
+	onDisconnect []func(err error)
+	// ID of the newest message handled, resume starts after it
+	lastID int64
+	// websocket connection ID from the hello event
+	socketID string
+}
+
+type Option func(*Client)
+
+// WithNickname sets the nickname messages are posted under and
+// mentions are received for. The server only accepts it for tokens with
+// the post scope, and never a nickname registered by someone else
+func WithNickname(nickname string) Option {
+	return func(c *Client) { c.nickname = nickname }
+}
+
+// WithHTTPClient sets the client for API requests, e.g. the one of an httptest.Server
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+func WithDialer(d *websocket.Dialer) Option {
+	return func(c *Client) { c.dialer = d }
+}
+
+// WithBackoff sets the delays between reconnection attempts,
+// doubled after every failure from min up to max
+func WithBackoff(min, max time.Duration) Option {
+	return func(c *Client) { c.minBackoff, c.maxBackoff = min, max }
+}
+
+// WithResumeFrom makes the first connection deliver messages newer than id,
+// e.g. the LastMessageID saved by a previous run
+func WithResumeFrom(id int64) Option {
+	return func(c *Client) { c.lastID = id }
+}
+
+// New returns a client of the chat at baseURL, its base path
+// like "https://example.com/chat". token is an API token
+// issued on the admin page, it may be empty for a read-only guest
+func New(baseURL, token string, opts ...Option) (*Client, error) {
+	base, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid chat URL: %w", err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("invalid chat URL %q: the scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		base:       base,
+		token:      token,
+		http:       &http.Client{Timeout: 30 * time.Second},
+		dialer:     websocket.DefaultDialer,
+		minBackoff: time.Second,
+		maxBackoff: time.Minute,
+		handlers:   make(map[string][]HandlerFunc),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// On registers a handler for events of the given type
+func (c *Client) On(eventType string, h HandlerFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[eventType] = append(c.handlers[eventType], h)
+}
+
+// OnEvent registers a handler for all events
+func (c *Client) OnEvent(h HandlerFunc) {
+	c.On("", h)
+}
+
+// OnMessage registers a handler for new messages, the client's own included
+func (c *Client) OnMessage(h func(ctx context.Context, m Message)) {
+	c.On(EventNewMessage, func(ctx context.Context, e Event) {
+		if m, err := e.Message(); err == nil {
+			h(ctx, m)
+		}
+	})
+}
+
+// OnMention registers a handler for mentions of the client nickname
+func (c *Client) OnMention(h func(ctx context.Context, m Mention)) {
+	c.On(EventMention, func(ctx context.Context, e Event) {
+		if m, err := e.Mention(); err == nil {
+			h(ctx, m)
+		}
+	})
+}
+
+// OnConnect registers a func called after every (re)connection,
+// before the missed messages are delivered
+func (c *Client) OnConnect(f func(ctx context.Context)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onConnect = append(c.onConnect, f)
+}
+
+// OnDisconnect registers a func called with the error
+// that ended a connection, before reconnecting
+func (c *Client) OnDisconnect(f func(err error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onDisconnect = append(c.onDisconnect, f)
+}
+
+// LastMessageID returns the ID of the newest message handled,
+// pass it to WithResumeFrom to continue after a restart
+func (c *Client) LastMessageID() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastID
+}
+
+// Run follows the chat until ctx is done, reconnecting on errors.
+// It returns early only if the server rejects the token
+// or there is no chat at the URL
+func (c *Client) Run(ctx context.Context) error {
+	backoff := c.minBackoff
+	for {
+		connected, err := c.session(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			switch apiErr.Status {
+			case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+				return err
+			}
+		}
+		for _, f := range c.disconnectFuncs() {
+			f(err)
+		}
+
+		if connected {
+			backoff = c.minBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, c.maxBackoff)
+	}
+}
+
+// runs a single connection, connected is false if it failed to open
+func (c *Client) session(ctx context.Context) (connected bool, err error) {
+	conn, resp, err := c.dialer.DialContext(ctx, c.socketURL(), c.header())
+	if err != nil {
+		if resp != nil {
+			return false, decodeError(resp)
+		}
+		return false, fmt.Errorf("error connecting: %w", err)
+	}
+	defer conn.Close()
+	// unblocks ReadMessage when ctx is done
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	conn.SetReadLimit(1 << 20)
+	conn.SetReadDeadline(time.Now().Add(readTimeout))
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(writeTimeout))
+	})
+
+	events := make(chan Event, 64)
+	readErr := make(chan error, 1)
+	// stops the reader when the session ends early
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(events)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			var e Event
+			if err := json.Unmarshal(data, &e); err != nil {
+				continue
+			}
+			select {
+			case events <- e:
+			case <-done:
+				readErr <- nil
+				return
+			}
+		}
+	}()
+
+	c.mu.Lock()
+	onConnect := append([]func(context.Context){}, c.onConnect...)
+	c.mu.Unlock()
+	for _, f := range onConnect {
+		f(ctx)
+	}
+
+	// live events wait in the channel, so messages stay in order
+	if err := c.resume(ctx); err != nil {
+		return true, err
+	}
+	for e := range events {
+		c.dispatch(ctx, e)
+	}
+	return true, <-readErr
+}
+
+// delivers the messages posted since the last handled one
+func (c *Client) resume(ctx context.Context) error {
+	for {
+		after := c.LastMessageID()
+		if after == 0 {
+			// nothing handled yet, history isn't replayed
+			return nil
+		}
+		page, err := c.Messages(ctx, MessageQuery{After: after, Limit: resumePage})
+		if err != nil {
+			return fmt.Errorf("error resuming: %w", err)
+		}
+		for _, m := range page.Messages {
+			data, _ := json.Marshal(m)
+			c.dispatch(ctx, Event{Type: EventNewMessage, Data: data, Resumed: true})
+		}
+		if !page.HasMore || len(page.Messages) == 0 {
+			return nil
+		}
+	}
+}
+
+func (c *Client) dispatch(ctx context.Context, e Event) {
+	c.mu.Lock()
+	switch e.Type {
+	case EventNewMessage:
+		var m struct {
+			ID int64 `json:"id"`
+		}
+		// resumed and live copies of a message may overlap
+		if json.Unmarshal(e.Data, &m) != nil || m.ID <= c.lastID {
+			c.mu.Unlock()
+			return
+		}
+		c.lastID = m.ID
+	case EventHello:
+		var hello struct {
+			ClientID string `json:"clientId"`
+		}
+		json.Unmarshal(e.Data, &hello)
+		c.socketID = hello.ClientID
+	}
+	handlers := append(append([]HandlerFunc{}, c.handlers[e.Type]...), c.handlers[""]...)
+	c.mu.Unlock()
+
+	for _, h := range handlers {
+		h(ctx, e)
+	}
+}
+
+func (c *Client) disconnectFuncs() []func(error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]func(error){}, c.onDisconnect...)
+}
+
+func (c *Client) socketURL() string {
+	u := *c.base
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	u.Path += "/ws"
+	if c.nickname != "" {
+		u.RawQuery = url.Values{"nickname": {c.nickname}}.Encode()
+	}
+	return u.String()
+}
+
+func (c *Client) header() http.Header {
+	h := http.Header{}
+	if c.token != "" {
+		h.Set("Authorization", "Bearer "+c.token)
+	}
+	return h
+}
@@ -0,0 +1,295 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/acakp/dumbchat/pkg/client"
+	"github.com/gorilla/websocket"
+)
+
+const token = "dct_test"
+
+// fakeChat serves the websocket and the messages API of a chat
+type fakeChat struct {
+	srv   *httptest.Server
+	conns chan *websocket.Conn
+
+	mu       sync.Mutex
+	messages []client.Message
+	sockets  int
+	// query nickname of the last websocket connection
+	nickname string
+	// the last posted message and its X-Dumbchat-Client header
+	posted   client.NewMessage
+	postedBy string
+}
+
+func newFakeChat(t *testing.T) *fakeChat {
+	chat := &fakeChat{conns: make(chan *websocket.Conn, 4)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /chat/ws", chat.serveWS)
+	mux.HandleFunc("GET /chat/api/v1/messages", chat.listMessages)
+	mux.HandleFunc("POST /chat/api/v1/messages", chat.postMessage)
+	chat.srv = httptest.NewServer(mux)
+	t.Cleanup(chat.srv.Close)
+	return chat
+}
+
+func (chat *fakeChat) url() string {
+	return chat.srv.URL + "/chat"
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"status": status, "message": message}})
+}
+
+func (chat *fakeChat) serveWS(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+token {
+		writeError(w, http.StatusUnauthorized, "Invalid or revoked API token")
+		return
+	}
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	chat.mu.Lock()
+	chat.sockets++
+	id := "socket-" + strconv.Itoa(chat.sockets)
+	chat.nickname = r.URL.Query().Get("nickname")
+	chat.mu.Unlock()
+
+	conn.WriteJSON(map[string]any{"type": client.EventHello, "data": map[string]string{"clientId": id}})
+	chat.conns <- conn
+}
+
+func (chat *fakeChat) listMessages(w http.ResponseWriter, r *http.Request) {
+	after, _ := strconv.ParseInt(r.URL.Query().Get("after"), 10, 64)
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	chat.mu.Lock()
+	page := client.MessagePage{Messages: []client.Message{}}
+	for _, m := range chat.messages {
+		if m.ID <= after {
+			continue
+		}
+		if len(page.Messages) == limit {
+			page.HasMore = true
+			break
+		}
+		page.Messages = append(page.Messages, m)
+	}
+	chat.mu.Unlock()
+	json.NewEncoder(w).Encode(page)
+}
+
+func (chat *fakeChat) postMessage(w http.ResponseWriter, r *http.Request) {
+	var m client.NewMessage
+	json.NewDecoder(r.Body).Decode(&m)
+	chat.mu.Lock()
+	chat.posted = m
+	chat.postedBy = r.Header.Get("X-Dumbchat-Client")
+	chat.mu.Unlock()
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(chat.add(1000))
+}
+
+// stores a message without sending it, as if it was posted while the client was away
+func (chat *fakeChat) add(id int64) client.Message {
+	m := client.Message{ID: id, Nickname: "alice", Content: "message " + strconv.FormatInt(id, 10)}
+	chat.mu.Lock()
+	chat.messages = append(chat.messages, m)
+	chat.mu.Unlock()
+	return m
+}
+
+// sends a stored message as a new_message event
+func (chat *fakeChat) send(t *testing.T, conn *websocket.Conn, id int64) {
+	t.Helper()
+	m := client.Message{ID: id, Nickname: "alice", Content: "message " + strconv.FormatInt(id, 10)}
+	if err := conn.WriteJSON(map[string]any{"type": client.EventNewMessage, "data": m}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (chat *fakeChat) accept(t *testing.T) *websocket.Conn {
+	t.Helper()
+	select {
+	case conn := <-chat.conns:
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("client didn't connect")
+		return nil
+	}
+}
+
+func run(t *testing.T, c *client.Client) <-chan error {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		done <- c.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+	})
+	return done
+}
+
+// waits for messages with the given IDs, in order
+func expectMessages(t *testing.T, got <-chan client.Message, ids ...int64) {
+	t.Helper()
+	for _, id := range ids {
+		select {
+		case m := <-got:
+			if m.ID != id {
+				t.Fatalf("got message %d, want %d", m.ID, id)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("message %d wasn't delivered", id)
+		}
+	}
+	select {
+	case m := <-got:
+		t.Fatalf("unexpected message %d", m.ID)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRunResumesAfterReconnect(t *testing.T) {
+	chat := newFakeChat(t)
+	c, err := client.New(chat.url(), token, client.WithBackoff(10*time.Millisecond, 20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(chan client.Message, 16)
+	c.OnMessage(func(_ context.Context, m client.Message) { got <- m })
+	connects := make(chan struct{}, 4)
+	c.OnConnect(func(context.Context) { connects <- struct{}{} })
+	disconnects := make(chan error, 4)
+	c.OnDisconnect(func(err error) { disconnects <- err })
+	run(t, c)
+
+	conn := chat.accept(t)
+	for _, id := range []int64{1, 2} {
+		chat.add(id)
+		chat.send(t, conn, id)
+	}
+	expectMessages(t, got, 1, 2)
+
+	// posted while the connection is down
+	chat.add(3)
+	chat.add(4)
+	conn.Close()
+	select {
+	case <-disconnects:
+	case <-time.After(5 * time.Second):
+		t.Fatal("disconnect wasn't noticed")
+	}
+
+	conn = chat.accept(t)
+	// the live copy of 4 overlaps with the resumed one
+	chat.add(5)
+	chat.send(t, conn, 4)
+	chat.send(t, conn, 5)
+	expectMessages(t, got, 3, 4, 5)
+
+	if len(connects) != 2 {
+		t.Errorf("OnConnect ran %d times, want 2", len(connects))
+	}
+	if id := c.LastMessageID(); id != 5 {
+		t.Errorf("LastMessageID() = %d, want 5", id)
+	}
+}
+
+func TestWithResumeFromPages(t *testing.T) {
+	chat := newFakeChat(t)
+	// more than one page of the resume requests
+	for id := int64(1); id <= 250; id++ {
+		chat.add(id)
+	}
+	c, err := client.New(chat.url(), token, client.WithResumeFrom(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(chan client.Message, 256)
+	resumed := true
+	// registered first, so it has run when the message arrives
+	c.On(client.EventNewMessage, func(_ context.Context, e client.Event) { resumed = resumed && e.Resumed })
+	c.OnMessage(func(_ context.Context, m client.Message) { got <- m })
+	run(t, c)
+	chat.accept(t)
+
+	ids := make([]int64, 0, 150)
+	for id := int64(101); id <= 250; id++ {
+		ids = append(ids, id)
+	}
+	expectMessages(t, got, ids...)
+	if !resumed {
+		t.Error("fetched messages aren't marked as resumed")
+	}
+}
+
+func TestSendUsesConnection(t *testing.T) {
+	chat := newFakeChat(t)
+	c, err := client.New(chat.url(), token, client.WithNickname("bot"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hello := make(chan struct{}, 1)
+	c.On(client.EventHello, func(context.Context, client.Event) { hello <- struct{}{} })
+	run(t, c)
+	chat.accept(t)
+	select {
+	case <-hello:
+	case <-time.After(5 * time.Second):
+		t.Fatal("hello wasn't delivered")
+	}
+
+	if _, err = c.Send(context.Background(), client.NewMessage{Content: "/not a command"}); err != nil {
+		t.Fatal(err)
+	}
+	chat.mu.Lock()
+	defer chat.mu.Unlock()
+	if chat.nickname != "bot" {
+		t.Errorf("connected as %q, want bot", chat.nickname)
+	}
+	if chat.posted.Nickname != "bot" || chat.posted.Content != "//not a command" {
+		t.Errorf("posted %+v", chat.posted)
+	}
+	// command replies come back over the connection
+	if chat.postedBy != "socket-1" {
+		t.Errorf("posted by %q, want socket-1", chat.postedBy)
+	}
+}
+
+func TestRunStopsOnRejectedToken(t *testing.T) {
+	chat := newFakeChat(t)
+	c, err := client.New(chat.url(), "dct_revoked", client.WithBackoff(time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err = <-run(t, c):
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run kept retrying")
+	}
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized {
+		t.Errorf("got %v, want a 401 APIError", err)
+	}
+}
@@ -0,0 +1,204 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// types of the events sent over the websocket
+const (
+	EventHello          = "hello"
+	EventNewMessage     = "new_message"
+	EventEditMessage    = "edit_message"
+	EventDeleteMessage  = "delete_message"
+	EventDeleteMessages = "delete_messages"
+	EventHideMessage    = "hide_message"
+	EventUnhideMessage  = "unhide_message"
+	EventReactionUpdate = "reaction_update"
+	EventMessagePreview = "message_preview"
+	EventPin            = "pin"
+	EventUnpin          = "unpin"
+	EventLockdown       = "lockdown"
+	EventSlowMode       = "slow_mode"
+	EventMention        = "mention"
+	EventCommandReply   = "command_reply"
+)
+
+// message kinds
+const (
+	KindUser   = "user"
+	KindBot    = "bot"
+	KindAction = "action"
+)
+
+// Event is a websocket event, Data is decoded with the Event methods
+// or json.Unmarshal into the type documented for Type
+type Event struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+	// set for messages fetched over the API while resuming
+	// after a reconnect, they weren't received as events
+	Resumed bool `json:"-"`
+}
+
+// Message decodes new_message, edit_message, delete_message, hide_message
+// and unhide_message events
+func (e Event) Message() (Message, error) {
+	var m Message
+	err := e.decode(&m)
+	return m, err
+}
+
+// Deleted decodes delete_messages events
+func (e Event) Deleted() (DeletedMessages, error) {
+	var d DeletedMessages
+	err := e.decode(&d)
+	return d, err
+}
+
+func (e Event) Mention() (Mention, error) {
+	var m Mention
+	err := e.decode(&m)
+	return m, err
+}
+
+func (e Event) ReactionUpdate() (ReactionUpdate, error) {
+	var u ReactionUpdate
+	err := e.decode(&u)
+	return u, err
+}
+
+func (e Event) Lockdown() (Lockdown, error) {
+	var l Lockdown
+	err := e.decode(&l)
+	return l, err
+}
+
+func (e Event) SlowMode() (SlowMode, error) {
+	var s SlowMode
+	err := e.decode(&s)
+	return s, err
+}
+
+func (e Event) CommandReply() (CommandReply, error) {
+	var c CommandReply
+	err := e.decode(&c)
+	return c, err
+}
+
+// Pin decodes pin and unpin events
+func (e Event) Pin() (Pin, error) {
+	var p Pin
+	err := e.decode(&p)
+	return p, err
+}
+
+func (e Event) decode(v any) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("error decoding %s event: %w", e.Type, err)
+	}
+	return nil
+}
+
+type Message struct {
+	ID        int64      `json:"id"`
+	Nickname  string     `json:"nickname"`
+	Content   string     `json:"content"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Hidden    bool       `json:"hidden"`
+	Pinned    bool       `json:"pinned"`
+	EditedAt  *time.Time `json:"editedAt,omitempty"`
+	ReplyTo   *int64     `json:"replyTo,omitempty"`
+	Verified  bool       `json:"verified,omitempty"`
+	Tripcode  string     `json:"tripcode,omitempty"`
+	// one of the Kind constants
+	Kind string `json:"kind"`
+	// only set on messages returned by the API
+	Reactions   []Reaction   `json:"reactions,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+	URL         string       `json:"url,omitempty"`
+}
+
+type Reaction struct {
+	Emoji string `json:"emoji"`
+	Count int    `json:"count"`
+}
+
+type Attachment struct {
+	Filename     string `json:"filename"`
+	MIME         string `json:"mime"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+}
+
+type MessagePage struct {
+	Messages []Message `json:"messages"`
+	HasMore  bool      `json:"hasMore"`
+}
+
+// MessageQuery selects a page of messages, zero fields aren't used
+type MessageQuery struct {
+	Before   int64
+	After    int64
+	Limit    int
+	Nickname string
+	From     time.Time
+	To       time.Time
+}
+
+type NewMessage struct {
+	// defaults to the client nickname
+	Nickname string `json:"nickname,omitempty"`
+	Content  string `json:"content"`
+	ReplyTo  *int64 `json:"replyTo,omitempty"`
+}
+
+type DeletedMessages struct {
+	IDs []int64 `json:"ids"`
+}
+
+type Mention struct {
+	ID        int64    `json:"id"`
+	Nickname  string   `json:"nickname"`
+	Mentioned []string `json:"mentioned"`
+}
+
+type ReactionUpdate struct {
+	ID        int64      `json:"id"`
+	Reactions []Reaction `json:"reactions"`
+}
+
+type Lockdown struct {
+	ReadOnly bool `json:"readOnly"`
+}
+
+type SlowMode struct {
+	Seconds int `json:"seconds"`
+}
+
+// CommandReply is the answer to a slash command, sent only to its issuer
+type CommandReply struct {
+	Command  string `json:"command"`
+	Text     string `json:"text"`
+	Nickname string `json:"nickname,omitempty"`
+}
+
+type Pin struct {
+	// "message" or "announcement"
+	Kind string `json:"kind"`
+	ID   int64  `json:"id"`
+}
+
+// APIError is an error response of the API
+type APIError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("dumbchat: %d %s", e.Status, e.Message)
+}
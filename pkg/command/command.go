@@ -0,0 +1,176 @@
+// Package command implements slash commands: chat lines starting with "/"
+// are run by a registered command instead of being posted verbatim.
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	ErrUnknown   = errors.New("unknown command")
+	ErrForbidden = errors.New("command is restricted to admins")
+	// returned by Run to show the command usage to the issuer
+	ErrUsage = errors.New("invalid command arguments")
+)
+
+var nameRe = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// Call is a single invocation of a command
+type Call struct {
+	// command name without the slash, lowercased
+	Name string
+	// text after the name, trimmed
+	Args     string
+	Nickname string
+	IP       string
+	IsAdmin  bool
+	// the request the command was posted with, for cookies and headers
+	Request *http.Request
+}
+
+// Result tells the chat what to do after a command has run
+type Result struct {
+	// text shown only to the issuer
+	Reply string
+	// posted as a message of the issuer when set
+	Post string
+	// marks Post as an action, rendered as "* nickname text"
+	Action bool
+	// switches the issuer's widget to this nickname
+	Nickname string
+}
+
+// Func runs a command. Errors made with Errorf are shown to the
+// issuer as is, other errors are reported as a failure
+type Func func(ctx context.Context, call Call) (Result, error)
+
+type Command struct {
+	Name string
+	// arguments shown by /help, e.g. "<nickname> [reason]"
+	Usage       string
+	Description string
+	// only admins may run it and see it in /help
+	Admin bool
+	Run   Func
+}
+
+// Error is an error meant for the issuer
+type Error struct {
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func Errorf(format string, args ...any) error {
+	return &Error{Message: fmt.Sprintf(format, args...)}
+}
+
+// Parse splits a chat line into the command name and its arguments.
+// ok is false for lines that aren't commands, like "/usr/bin" or "/ hi".
+// "//" escapes a leading slash
+func Parse(line string) (name, args string, ok bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "/") || strings.HasPrefix(line, "//") {
+		return "", "", false
+	}
+	name = line[1:]
+	if i := strings.IndexFunc(name, unicode.IsSpace); i >= 0 {
+		name, args = name[:i], name[i:]
+	}
+	name = strings.ToLower(name)
+	if !nameRe.MatchString(name) {
+		return "", "", false
+	}
+	return name, strings.TrimSpace(args), true
+}
+
+// Unescape drops the escaping slash of a "//" line
+func Unescape(line string) string {
+	trimmed := strings.TrimLeft(line, " \t\r\n")
+	if strings.HasPrefix(trimmed, "//") {
+		return trimmed[1:]
+	}
+	return line
+}
+
+// Registry holds the commands, safe for concurrent use
+type Registry struct {
+	mu       sync.RWMutex
+	commands map[string]Command
+}
+
+func NewRegistry() *Registry {
+	return &Registry{commands: make(map[string]Command)}
+}
+
+// Register adds a command, replacing one with the same name
+func (r *Registry) Register(c Command) error {
+	c.Name = strings.ToLower(c.Name)
+	if !nameRe.MatchString(c.Name) {
+		return fmt.Errorf("invalid command name %q", c.Name)
+	}
+	if c.Run == nil {
+		return fmt.Errorf("command %q has no Run func", c.Name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands[c.Name] = c
+	return nil
+}
+
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.commands, strings.ToLower(name))
+}
+
+func (r *Registry) Lookup(name string) (Command, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.commands[strings.ToLower(name)]
+	return c, ok
+}
+
+// Commands returns the commands available to admins or everyone, by name
+func (r *Registry) Commands(isAdmin bool) []Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []Command
+	for _, c := range r.commands {
+		if !c.Admin || isAdmin {
+			list = append(list, c)
+		}
+	}
+	slices.SortFunc(list, func(a, b Command) int { return strings.Compare(a.Name, b.Name) })
+	return list
+}
+
+// Run looks up and runs the called command
+func (r *Registry) Run(ctx context.Context, call Call) (Result, error) {
+	c, ok := r.Lookup(call.Name)
+	if !ok {
+		return Result{}, ErrUnknown
+	}
+	if c.Admin && !call.IsAdmin {
+		return Result{}, ErrForbidden
+	}
+	return c.Run(ctx, call)
+}
+
+// Help is the usage line of a command, e.g. "/ban <nickname> [reason]"
+func (c Command) Help() string {
+	if c.Usage == "" {
+		return "/" + c.Name
+	}
+	return "/" + c.Name + " " + c.Usage
+}
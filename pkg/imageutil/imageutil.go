@@ -0,0 +1,92 @@
+package imageutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // registers the gif decoder
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// larger images are rejected before decoding,
+// so a tiny file can't expand into gigabytes of pixels
+const MaxPixels = 40_000_000
+
+var ErrTooLarge = errors.New("image dimensions are too large")
+
+// Image is a decoded upload
+type Image struct {
+	Img    image.Image
+	Format string
+	// file contents without metadata
+	Data []byte
+}
+
+// Clean decodes an image and encodes it again, which drops
+// all metadata such as EXIF and GPS tags. GIFs are kept as is
+// to preserve animation, the format doesn't carry EXIF
+func Clean(data []byte) (Image, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, fmt.Errorf("error reading image: %w", err)
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return Image{}, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, fmt.Errorf("error decoding image: %w", err)
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	case "png":
+		err = png.Encode(&buf, img)
+	case "gif":
+		_, err = buf.Write(data)
+	default:
+		return Image{}, fmt.Errorf("unsupported image format %q", format)
+	}
+	if err != nil {
+		return Image{}, fmt.Errorf("error encoding image: %w", err)
+	}
+
+	return Image{Img: img, Format: format, Data: buf.Bytes()}, nil
+}
+
+// Thumbnail scales the image down to fit into a maxSide square.
+// Photos are encoded as JPEG, everything else as PNG to keep transparency.
+// Returns the encoded thumbnail and its file extension
+func Thumbnail(img Image, maxSide int) ([]byte, string, error) {
+	b := img.Img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > maxSide || h > maxSide {
+		if w >= h {
+			w, h = maxSide, max(1, h*maxSide/w)
+		} else {
+			w, h = max(1, w*maxSide/h), maxSide
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img.Img, b, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if img.Format == "jpeg" {
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+			return nil, "", fmt.Errorf("error encoding thumbnail: %w", err)
+		}
+		return buf.Bytes(), ".jpg", nil
+	}
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, "", fmt.Errorf("error encoding thumbnail: %w", err)
+	}
+	return buf.Bytes(), ".png", nil
+}
@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/hlog"
-	"github.com/rs/zerolog/log"
 )
 
 func Middleware(next http.Handler) http.Handler {
@@ -18,9 +17,9 @@ func Middleware(next http.Handler) http.Handler {
 
 		event := l.Info()
 		if status >= 500 {
-			event = log.Error()
+			event = l.Error()
 		} else if status >= 400 {
-			event = log.Warn()
+			event = l.Warn()
 		}
 		event.
 			Dur("duration", duration).
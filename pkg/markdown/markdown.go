@@ -0,0 +1,149 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Options struct {
+	// wrap @nickname mentions into <span class="mention">
+	Mentions bool
+}
+
+// Render converts a restricted Markdown subset to HTML:
+// **bold**, __bold__, *italic*, _italic_, `inline code`,
+// ``` code blocks ``` and auto-linked http(s) URLs.
+// Everything else is escaped, and the result is passed through
+// the sanitizer, so the output is always safe to embed into a page.
+func Render(src string, opts Options) string {
+	var b strings.Builder
+
+	// split by code fences: even parts are text, odd parts are code blocks
+	parts := strings.Split(src, "```")
+	if len(parts)%2 == 0 {
+		// unterminated fence is rendered as plain text
+		last := len(parts) - 1
+		parts[last-1] = parts[last-1] + "```" + parts[last]
+		parts = parts[:last]
+	}
+	for i, part := range parts {
+		if i%2 == 1 {
+			b.WriteString(codeBlock(part))
+			continue
+		}
+		if i > 0 {
+			// <pre> already breaks the line
+			part = strings.TrimPrefix(part, "\n")
+		}
+		b.WriteString(inline(part, opts))
+	}
+
+	return Sanitize(b.String())
+}
+
+// Plain escapes the text without any Markdown formatting
+func Plain(src string, opts Options) string {
+	text := html.EscapeString(src)
+	if opts.Mentions {
+		text = highlightMentions(text)
+	}
+	return Sanitize(text)
+}
+
+func codeBlock(code string) string {
+	// drop the language hint and the line break after the opening fence
+	if nl := strings.IndexByte(code, '\n'); nl >= 0 && !strings.ContainsAny(code[:nl], " \t") {
+		code = code[nl+1:]
+	}
+	code = strings.TrimSuffix(code, "\n")
+	return "<pre><code>" + html.EscapeString(code) + "</code></pre>"
+}
+
+var inlineCodeRe = regexp.MustCompile("`([^`\n]+)`")
+
+func inline(text string, opts Options) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range inlineCodeRe.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(formatText(text[last:loc[0]], opts))
+		b.WriteString("<code>" + html.EscapeString(text[loc[2]:loc[3]]) + "</code>")
+		last = loc[1]
+	}
+	b.WriteString(formatText(text[last:], opts))
+	return b.String()
+}
+
+var (
+	urlRe    = regexp.MustCompile(`https?://[^\s<>"']+`)
+	boldRe   = regexp.MustCompile(`\*\*([^*\n]+?)\*\*|__([^_\n]+?)__`)
+	italicRe = regexp.MustCompile(`(^|[^\w*])\*([^*\s][^*\n]*?)\*|(^|[^\w_])_([^_\s][^_\n]*?)_`)
+	// cut-out links and mentions, NULs are dropped from the text
+	placeholderRe = regexp.MustCompile("\x00([0-9]+)\x00")
+)
+
+// trailing punctuation usually ends the sentence, not the URL
+func trimURL(url string) string {
+	return strings.TrimRight(url, ".,;:!?)")
+}
+
+// Links returns http(s) URLs in the text outside of code,
+// the same ones Render turns into links
+func Links(src string) []string {
+	var links []string
+	parts := strings.Split(src, "```")
+	for i, part := range parts {
+		// an unterminated fence is plain text
+		if i%2 == 1 && i < len(parts)-1 {
+			continue
+		}
+		part = inlineCodeRe.ReplaceAllString(part, "")
+		for _, url := range urlRe.FindAllString(part, -1) {
+			links = append(links, trimURL(url))
+		}
+	}
+	return links
+}
+
+// escapes plain text and applies emphasis and auto-links
+func formatText(text string, opts Options) string {
+	// links and mentions are cut out before emphasis is applied,
+	// so underscores and asterisks inside them aren't treated as one
+	// placeholders are made of NULs, which are never rendered
+	text = strings.ReplaceAll(text, "\x00", "")
+	var cut []string
+	placeholder := func(markup string) string {
+		cut = append(cut, markup)
+		return fmt.Sprintf("\x00%d\x00", len(cut)-1)
+	}
+	text = urlRe.ReplaceAllStringFunc(text, func(url string) string {
+		trimmed := trimURL(url)
+		escaped := html.EscapeString(trimmed)
+		link := `<a href="` + escaped + `" rel="nofollow ugc noopener" target="_blank">` + escaped + `</a>`
+		return placeholder(link) + url[len(trimmed):]
+	})
+
+	text = html.EscapeString(text)
+	if opts.Mentions {
+		text = mentionRe.ReplaceAllStringFunc(text, func(m string) string {
+			sub := mentionRe.FindStringSubmatch(m)
+			return sub[1] + placeholder(mentionSpan(sub[2]))
+		})
+	}
+	text = boldRe.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = italicRe.ReplaceAllStringFunc(text, func(m string) string {
+		sub := italicRe.FindStringSubmatch(m)
+		if sub[2] != "" {
+			return sub[1] + "<em>" + sub[2] + "</em>"
+		}
+		return sub[3] + "<em>" + sub[4] + "</em>"
+	})
+	text = strings.ReplaceAll(text, "\n", "<br>")
+
+	return placeholderRe.ReplaceAllStringFunc(text, func(m string) string {
+		i, _ := strconv.Atoi(strings.Trim(m, "\x00"))
+		return cut[i]
+	})
+}
@@ -0,0 +1,100 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"bold and italic", "**a** _b_ *c*", "<strong>a</strong> <em>b</em> <em>c</em>"},
+		{"inline code", "`**a**`", "<code>**a**</code>"},
+		{"code block", "```go\n<b>\n```", "<pre><code>&lt;b&gt;</code></pre>"},
+		{"link", "see https://example.com/a_b_c.", `see <a href="https://example.com/a_b_c" rel="nofollow ugc noopener" target="_blank">https://example.com/a_b_c</a>.`},
+		{"mention", "hi @bob", `hi <span class="mention" data-mention="bob">@bob</span>`},
+		{"underscored mention", "@_x_", `<span class="mention" data-mention="_x_">@_x_</span>`},
+		{"mention with a dot", "@a.b.", `<span class="mention" data-mention="a.b">@a.b</span>.`},
+		{"bold mention", "**@bob**", `<strong><span class="mention" data-mention="bob">@bob</span></strong>`},
+		{"e-mail", "mail@example.com", "mail@example.com"},
+		{"placeholder in text", "\x000\x00 x", "0 x"},
+	}
+	for _, tt := range tests {
+		if got := Render(tt.src, Options{Mentions: true}); got != tt.want {
+			t.Errorf("%s: Render(%q) = %q, want %q", tt.name, tt.src, got, tt.want)
+		}
+	}
+}
+
+var (
+	tagRe     = regexp.MustCompile(`<[^>]*>`)
+	tagNameRe = regexp.MustCompile(`^</?(strong|em|code|pre|br|a|span)[ >]`)
+	badAttrRe = regexp.MustCompile(`(?i)\son\w+=|javascript:|style=`)
+)
+
+// nothing a visitor writes may end up as active markup
+func TestRenderEscapes(t *testing.T) {
+	tests := []string{
+		"<script>alert(1)</script>",
+		"<img src=x onerror=alert(1)>",
+		"javascript:alert(1)",
+		"[x](javascript:alert(1))",
+		`https://example.com/"onmouseover="alert(1)`,
+		`https://example.com/'onmouseover='alert(1)`,
+		"```\n</code></pre><script>alert(1)</script>\n```",
+		"`<script>`",
+		`@a"onclick="alert(1)`,
+		"**<svg onload=alert(1)>**",
+		"_<a href=javascript:alert(1)>x</a>_",
+	}
+	for _, src := range tests {
+		for _, opts := range []Options{{}, {Mentions: true}} {
+			for name, got := range map[string]string{"Render": Render(src, opts), "Plain": Plain(src, opts)} {
+				for _, tag := range tagRe.FindAllString(got, -1) {
+					if !tagNameRe.MatchString(tag) || badAttrRe.MatchString(tag) {
+						t.Errorf("%s(%q) = %q, has tag %q", name, src, got, tag)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{`<a href="javascript:alert(1)">x</a>`, "x"},
+		{`<a href="https://example.com" onclick="alert(1)">x</a>`, `<a href="https://example.com">x</a>`},
+		{`<span class="mention" data-mention="bob" onmouseover="alert(1)">@bob</span>`, `<span class="mention" data-mention="bob">@bob</span>`},
+		{`<span class="evil" data-mention="a&quot;b">x</span>`, "<span>x</span>"},
+		{`<em style="color:red">x</em>`, "<em>x</em>"},
+		{`<iframe src="https://example.com"></iframe>x`, "x"},
+		{`<script>alert(1)</script>x`, "x"},
+	}
+	for _, tt := range tests {
+		if got := Sanitize(tt.src); got != tt.want {
+			t.Errorf("Sanitize(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"@bob and @Bob", []string{"bob"}},
+		{"@_x_ @a.b.", []string{"_x_", "a.b"}},
+		{"mail@example.com @@bob &#@bob", nil},
+		{"(@алиса)", []string{"алиса"}},
+	}
+	for _, tt := range tests {
+		got := Mentions(tt.text)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Mentions(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
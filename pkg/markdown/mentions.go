@@ -0,0 +1,37 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// @nickname preceded by start of text or a non-word character,
+// so e-mail addresses aren't treated as mentions
+var mentionRe = regexp.MustCompile(`(^|[^\w@&#])@([\p{L}\p{N}_](?:[\p{L}\p{N}_.\-]{0,30}[\p{L}\p{N}_])?)`)
+
+// Mentions returns unique nicknames mentioned in the text, in order of appearance
+func Mentions(text string) []string {
+	var nicknames []string
+	seen := make(map[string]bool)
+	for _, m := range mentionRe.FindAllStringSubmatch(text, -1) {
+		key := strings.ToLower(m[2])
+		if !seen[key] {
+			seen[key] = true
+			nicknames = append(nicknames, m[2])
+		}
+	}
+	return nicknames
+}
+
+// wraps mentions in already escaped text into spans
+func highlightMentions(escaped string) string {
+	return mentionRe.ReplaceAllStringFunc(escaped, func(m string) string {
+		sub := mentionRe.FindStringSubmatch(m)
+		return sub[1] + mentionSpan(sub[2])
+	})
+}
+
+// nicknames matched by mentionRe need no escaping
+func mentionSpan(nickname string) string {
+	return `<span class="mention" data-mention="` + nickname + `">@` + nickname + `</span>`
+}
@@ -0,0 +1,31 @@
+package markdown
+
+import (
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// allows only the markup produced by Render
+var policy = newPolicy()
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements("strong", "em", "code", "pre", "br")
+
+	p.AllowURLSchemes("http", "https")
+	p.RequireParseableURLs(true)
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowAttrs("rel").Matching(regexp.MustCompile(`^nofollow ugc noopener$`)).OnElements("a")
+	p.AllowAttrs("target").Matching(regexp.MustCompile(`^_blank$`)).OnElements("a")
+
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^mention$`)).OnElements("span")
+	p.AllowAttrs("data-mention").Matching(regexp.MustCompile(`^[\p{L}\p{N}_.\-]+$`)).OnElements("span")
+
+	return p
+}
+
+// Sanitize strips everything except the whitelisted markup
+func Sanitize(s string) string {
+	return policy.Sanitize(s)
+}
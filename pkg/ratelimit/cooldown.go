@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Cooldown allows one action per interval for every key,
+// the interval can be changed at any time. A zero interval allows everything
+type Cooldown struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     map[string]time.Time
+}
+
+func NewCooldown() *Cooldown {
+	return &Cooldown{last: make(map[string]time.Time)}
+}
+
+func (c *Cooldown) SetInterval(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interval = max(d, 0)
+	if c.interval == 0 {
+		clear(c.last)
+	}
+}
+
+func (c *Cooldown) Interval() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.interval
+}
+
+// Allow records an action for the key, or returns false
+// and how long to wait when the previous one was too recent
+func (c *Cooldown) Allow(key string) (bool, time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.interval == 0 {
+		return true, 0
+	}
+
+	now := time.Now()
+	if wait := c.last[key].Add(c.interval).Sub(now); wait > 0 {
+		return false, wait
+	}
+	c.last[key] = now
+	for key, t := range c.last {
+		if now.Sub(t) > c.interval {
+			delete(c.last, key)
+		}
+	}
+	return true, 0
+}
+
+// Release forgets the action recorded for the key, for one that failed
+// after Allow, so the next one doesn't have to wait
+func (c *Cooldown) Release(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.last, key)
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Keyed keeps a separate token bucket for every key (IP, token, etc.)
+type Keyed struct {
+	mu       sync.Mutex
+	limit    rate.Limit
+	burst    int
+	limiters map[string]*entry
+	lastGC   time.Time
+}
+
+type entry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// idle buckets are full again after this long and can be dropped
+const idleTTL = 10 * time.Minute
+
+func New(limit rate.Limit, burst int) *Keyed {
+	return &Keyed{
+		limit:    limit,
+		burst:    burst,
+		limiters: make(map[string]*entry),
+		lastGC:   time.Now(),
+	}
+}
+
+func (k *Keyed) Allow(key string) bool {
+	return k.AllowN(key, k.limit, k.burst)
+}
+
+// same as Allow, but with a custom rate for this key,
+// e.g. a per-token limit
+func (k *Keyed) AllowN(key string, limit rate.Limit, burst int) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(k.lastGC) > idleTTL {
+		for key, e := range k.limiters {
+			if now.Sub(e.lastSeen) > idleTTL {
+				delete(k.limiters, key)
+			}
+		}
+		k.lastGC = now
+	}
+
+	e, ok := k.limiters[key]
+	if !ok || e.limiter.Limit() != limit || e.limiter.Burst() != burst {
+		e = &entry{limiter: rate.NewLimiter(limit, burst)}
+		k.limiters[key] = e
+	}
+	e.lastSeen = now
+	return e.limiter.Allow()
+}
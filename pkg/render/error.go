@@ -3,10 +3,11 @@ package render
 import (
 	"net/http"
 
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog/hlog"
 )
 
-func Error(w http.ResponseWriter, err error, status int, message string) {
-	log.Error().Err(err).Msg(message)
+// logs err through the request's logger and sends message as plain text
+func Error(w http.ResponseWriter, r *http.Request, err error, status int, message string) {
+	hlog.FromRequest(r).Error().Err(err).Msg(message)
 	http.Error(w, message, status)
 }
@@ -0,0 +1,32 @@
+package render
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog/hlog"
+)
+
+// body of JSON error responses
+type ErrorBody struct {
+	Error ErrorDetail `json:"error"`
+}
+
+type ErrorDetail struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+func JSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		hlog.FromRequest(r).Error().Err(err).Msg("Failed to encode JSON response")
+	}
+}
+
+// same as Error, but the message is sent as ErrorBody
+func JSONError(w http.ResponseWriter, r *http.Request, err error, status int, message string) {
+	hlog.FromRequest(r).Error().Err(err).Msg(message)
+	JSON(w, r, status, ErrorBody{Error: ErrorDetail{Status: status, Message: message}})
+}
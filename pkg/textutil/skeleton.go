@@ -0,0 +1,96 @@
+package textutil
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// letters of other scripts drawn like Latin ones, mapped before lowercasing
+// because some only look alike in one case (Cyrillic "В" and "в")
+var upperConfusables = map[rune]rune{
+	// Cyrillic
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O',
+	'Р': 'P', 'С': 'C', 'Т': 'T', 'Х': 'X', 'Ѕ': 'S', 'І': 'I', 'Ј': 'J',
+	// Greek
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K',
+	'Μ': 'M', 'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+}
+
+var lowerConfusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y', 'х': 'x',
+	'ѕ': 's', 'і': 'i', 'ј': 'j', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'һ': 'h',
+	// Greek
+	'α': 'a', 'ο': 'o', 'ν': 'v', 'ι': 'i',
+	// l looks like a capital I, lowercased to i
+	'l': 'i', '0': 'o', '1': 'i', 'ı': 'i',
+}
+
+// Skeleton maps s to a key shared by the strings that look alike:
+// compatibility forms are unified, accents dropped, letters of other
+// scripts that are drawn like Latin ones replaced and case folded.
+// "Аdmin" with a Cyrillic А, "ADMIN" and "admín" have the same skeleton
+func Skeleton(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if c, ok := upperConfusables[r]; ok {
+			r = c
+		}
+		r = unicode.ToLower(r)
+		if c, ok := lowerConfusables[r]; ok {
+			r = c
+		}
+		b.WriteRune(r)
+	}
+	return strings.ReplaceAll(b.String(), "rn", "m")
+}
+
+// scripts commonly written together, see Unicode TS #39 "Highly Restrictive"
+var scriptSets = [][]*unicode.RangeTable{
+	{unicode.Latin, unicode.Han, unicode.Hiragana, unicode.Katakana},
+	{unicode.Latin, unicode.Han, unicode.Bopomofo},
+	{unicode.Latin, unicode.Han, unicode.Hangul},
+}
+
+// SingleScript reports whether the letters of s come from a single script,
+// or from scripts commonly written together like Han and Hiragana.
+// Digits, punctuation and combining marks belong to any script
+func SingleScript(s string) bool {
+	var used []*unicode.RangeTable
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		script := scriptOf(r)
+		if script != nil && !slices.Contains(used, script) {
+			used = append(used, script)
+		}
+	}
+	if len(used) <= 1 {
+		return true
+	}
+	for _, set := range scriptSets {
+		if !slices.ContainsFunc(used, func(t *unicode.RangeTable) bool { return !slices.Contains(set, t) }) {
+			return true
+		}
+	}
+	return false
+}
+
+func scriptOf(r rune) *unicode.RangeTable {
+	for name, table := range unicode.Scripts {
+		if name == "Common" || name == "Inherited" {
+			continue
+		}
+		if unicode.Is(table, r) {
+			return table
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package textutil
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// Clean makes user input safe to store and display: invalid UTF-8 is replaced,
+// line endings are normalized, and control and bidi formatting characters
+// (which can reorder surrounding text) are removed. Line breaks and tabs are
+// kept only if multiline is set
+func Clean(s string, multiline bool) string {
+	s = strings.ToValidUTF8(s, "\ufffd")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			if multiline {
+				return r
+			}
+			return ' '
+		case unicode.IsControl(r), isBidiControl(r):
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// explicit directional embeddings, overrides, isolates and marks
+func isBidiControl(r rune) bool {
+	switch {
+	case r >= '\u202a' && r <= '\u202e',
+		r >= '\u2066' && r <= '\u2069',
+		r == '\u200e', r == '\u200f', r == '\u061c':
+		return true
+	}
+	return false
+}
+
+// Length counts user-perceived characters (grapheme clusters),
+// so "👍🏽" or "é" written with a combining accent count as one
+func Length(s string) int {
+	return uniseg.GraphemeClusterCount(s)
+}
@@ -0,0 +1,97 @@
+package textutil
+
+import "testing"
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name, in  string
+		multiline bool
+		want      string
+	}{
+		{"plain", "hello", false, "hello"},
+		{"line breaks", "a\r\nb\tc", true, "a\nb\tc"},
+		{"single line", "a\r\nb\tc", false, "a b c"},
+		{"control characters", "a\x00b\x07c\x1b[31m", true, "abc[31m"},
+		{"bidi override", "abc\u202egpj.exe", false, "abcgpj.exe"},
+		{"bidi isolates and marks", "\u2066a\u2069\u200eb\u200f\u061c", false, "ab"},
+		{"invalid UTF-8", "a\xffb", false, "a�b"},
+		{"combining marks are kept", "e\u0301", false, "e\u0301"},
+		{"zero width joiner is kept", "👩\u200d💻", false, "👩\u200d💻"},
+	}
+	for _, tt := range tests {
+		if got := Clean(tt.in, tt.multiline); got != tt.want {
+			t.Errorf("%s: Clean(%q, %v) = %q, want %q", tt.name, tt.in, tt.multiline, got, tt.want)
+		}
+	}
+}
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"é", 1},
+		{"e\u0301", 1},
+		{"z\u0351\u0352\u0353", 1},
+		{"👍🏽", 1},
+		{"👩\u200d💻", 1},
+		{"👨\u200d👩\u200d👧\u200d👦", 1},
+		{"🇺🇦🇯🇵", 2},
+		{"日本語", 3},
+	}
+	for _, tt := range tests {
+		if got := Length(tt.in); got != tt.want {
+			t.Errorf("Length(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	same := [][]string{
+		{"admin", "ADMIN", "Admin", "аdmin", "АDMIN", "admín", "admın", "ａｄｍｉｎ", "adrnin"},
+		{"paypal", "раураl", "PAYPA1", "ΡAYPAL"},
+		{"bob", "bоb", "b0b"},
+		{"jill", "j1ll", "jiii", "јill"},
+	}
+	for _, group := range same {
+		want := Skeleton(group[0])
+		for _, s := range group[1:] {
+			if got := Skeleton(s); got != want {
+				t.Errorf("Skeleton(%q) = %q, want %q like %q", s, got, want, group[0])
+			}
+		}
+	}
+
+	different := [][2]string{{"admin", "admins"}, {"bob", "rob"}, {"алиса", "alice"}, {"mod", "god"}}
+	for _, pair := range different {
+		if Skeleton(pair[0]) == Skeleton(pair[1]) {
+			t.Errorf("%q and %q have the same skeleton %q", pair[0], pair[1], Skeleton(pair[0]))
+		}
+	}
+}
+
+func TestSingleScript(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"bob", true},
+		{"bob_42", true},
+		{"алиса", true},
+		{"José", true},
+		{"e\u0301", true},
+		{"東京タワー", true},
+		{"山田taro", true},
+		{"한국abc", true},
+		{"аdmin", false},
+		{"bobβ", false},
+		{"한국カタ", false},
+	}
+	for _, tt := range tests {
+		if got := SingleScript(tt.in); got != tt.want {
+			t.Errorf("SingleScript(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
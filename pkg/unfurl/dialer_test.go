@@ -0,0 +1,58 @@
+package unfurl
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+		{"::1", false},
+		{"fc00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublic(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("IsPublic(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestSafeDialerRejectsInternalAddresses(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	for _, addr := range []string{ln.Addr().String(), "10.0.0.1:80", "[::1]:80"} {
+		conn, err := SafeDialer(time.Second).Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			t.Errorf("dialing %s succeeded", addr)
+			continue
+		}
+		if !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("dialing %s: got %v, want ErrForbiddenAddress", addr, err)
+		}
+	}
+}
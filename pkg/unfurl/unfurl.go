@@ -0,0 +1,261 @@
+package unfurl
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	// only the head of the page is needed
+	maxBodyBytes = 512 << 10
+	// larger preview images are skipped
+	maxImageBytes = 2 << 20
+	maxRedirects  = 3
+	userAgent     = "dumbchat-unfurl/1.0 (+link previews)"
+)
+
+var (
+	ErrNotHTML       = errors.New("not an html page")
+	ErrNotImage      = errors.New("not a png, jpeg, gif or webp image")
+	ErrImageTooLarge = errors.New("image too large")
+)
+
+// image types kept for previews, decided by sniffing rather than the header
+var imageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Preview is the metadata of a linked page
+type Preview struct {
+	URL         string
+	Title       string
+	Description string
+	Image       string
+	SiteName    string
+}
+
+func (p Preview) IsEmpty() bool {
+	return p.Title == "" && p.Description == ""
+}
+
+// Unfurler fetches link previews
+type Unfurler struct {
+	client *http.Client
+}
+
+// New returns an unfurler that only connects to public addresses
+func New(timeout time.Duration) *Unfurler {
+	transport := &http.Transport{
+		// a proxy from the environment would bypass the address check
+		Proxy:                 nil,
+		DialContext:           SafeDialer(timeout).DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+	return NewWithClient(&http.Client{Transport: transport, Timeout: timeout})
+}
+
+// NewWithClient uses the given client as is, e.g. one of httptest.Server.
+// It must guard against internal addresses itself
+func NewWithClient(client *http.Client) *Unfurler {
+	c := *client
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return errors.New("too many redirects")
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return fmt.Errorf("unsupported redirect scheme %q", req.URL.Scheme)
+		}
+		return nil
+	}
+	return &Unfurler{client: &c}
+}
+
+// Fetch downloads the page and reads its Open Graph,
+// Twitter card and basic HTML metadata
+func (u *Unfurler) Fetch(ctx context.Context, rawURL string) (Preview, error) {
+	pageURL, err := url.Parse(rawURL)
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		return Preview{}, fmt.Errorf("invalid url %q", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL.String(), nil)
+	if err != nil {
+		return Preview{}, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return Preview{}, fmt.Errorf("error fetching %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Preview{}, fmt.Errorf("error fetching %s: status %d", rawURL, resp.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return Preview{}, ErrNotHTML
+	}
+
+	p := parse(io.LimitReader(resp.Body, maxBodyBytes))
+	p.URL = rawURL
+	// relative image paths are resolved against the final location
+	if p.Image != "" {
+		p.Image = resolve(resp.Request.URL, p.Image)
+	}
+	return p, nil
+}
+
+// FetchImage downloads a preview image, so it can be served from the chat
+// instead of being hot-linked. Returns the image and its sniffed type
+func (u *Unfurler) FetchImage(ctx context.Context, rawURL string) ([]byte, string, error) {
+	imageURL, err := url.Parse(rawURL)
+	if err != nil || (imageURL.Scheme != "http" && imageURL.Scheme != "https") || imageURL.Host == "" {
+		return nil, "", fmt.Errorf("invalid url %q", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL.String(), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("error fetching %s: status %d", rawURL, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading %s: %w", rawURL, err)
+	}
+	if len(data) > maxImageBytes {
+		return nil, "", ErrImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if !imageTypes[contentType] {
+		return nil, "", ErrNotImage
+	}
+	return data, contentType, nil
+}
+
+// reads metadata from the page head
+func parse(r io.Reader) Preview {
+	var p, twitter Preview
+	var title, description string
+	inTitle := false
+
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return merge(p, twitter, Preview{Title: title, Description: description})
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			switch tok.Data {
+			case "title":
+				inTitle = title == ""
+			case "meta":
+				key, content := metaAttrs(tok)
+				switch key {
+				case "og:title":
+					p.Title = content
+				case "og:description":
+					p.Description = content
+				case "og:image", "og:image:url":
+					if p.Image == "" {
+						p.Image = content
+					}
+				case "og:site_name":
+					p.SiteName = content
+				case "twitter:title":
+					twitter.Title = content
+				case "twitter:description":
+					twitter.Description = content
+				case "twitter:image", "twitter:image:src":
+					twitter.Image = content
+				case "description":
+					description = content
+				}
+			case "body":
+				// metadata lives in the head
+				return merge(p, twitter, Preview{Title: title, Description: description})
+			}
+		case html.TextToken:
+			if inTitle {
+				title = strings.TrimSpace(string(z.Text()))
+				inTitle = false
+			}
+		case html.EndTagToken:
+			inTitle = false
+		}
+	}
+}
+
+func metaAttrs(tok html.Token) (key, content string) {
+	for _, a := range tok.Attr {
+		switch a.Key {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(a.Val))
+			}
+		case "content":
+			content = strings.TrimSpace(a.Val)
+		}
+	}
+	return key, content
+}
+
+// takes the first non-empty value of every field
+func merge(previews ...Preview) Preview {
+	var p Preview
+	for _, o := range previews {
+		p.Title = cmp.Or(p.Title, o.Title)
+		p.Description = cmp.Or(p.Description, o.Description)
+		p.Image = cmp.Or(p.Image, o.Image)
+		p.SiteName = cmp.Or(p.SiteName, o.SiteName)
+	}
+	p.Title = truncate(p.Title, 200)
+	p.Description = truncate(p.Description, 300)
+	return p
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// resolves ref against base, only http(s) results are kept
+func resolve(base *url.URL, ref string) string {
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
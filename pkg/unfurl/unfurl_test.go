@@ -0,0 +1,150 @@
+package unfurl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/og", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<html><head>
+			<title>Page title</title>
+			<meta property="og:title" content=" OG title ">
+			<meta property="og:description" content="OG description">
+			<meta property="og:image" content="/img/cover.png">
+			<meta property="og:site_name" content="Example">
+			<meta name="twitter:title" content="Twitter title">
+		</head><body><meta property="og:title" content="in body"></body></html>`)
+	})
+	mux.HandleFunc("/fallback", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>Plain</title>
+			<meta name="twitter:description" content="Twitter description">
+			<meta name="description" content="Meta description">
+		</head></html>`)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/articles/og", http.StatusFound)
+	})
+	mux.HandleFunc("/articles/og", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<head><meta property="og:title" content="Moved"><meta property="og:image" content="cover.png"></head>`)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"title": "not a page"}`)
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	mux.HandleFunc("/img/cover.png", func(w http.ResponseWriter, r *http.Request) {
+		// the header is ignored, the type is sniffed
+		w.Header().Set("Content-Type", "text/html")
+		w.Write(pngHeader)
+	})
+	mux.HandleFunc("/img/fake.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprint(w, `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`)
+	})
+	mux.HandleFunc("/img/huge.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(pngHeader)
+		w.Write(make([]byte, maxImageBytes))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetch(t *testing.T) {
+	srv := newServer(t)
+	u := NewWithClient(srv.Client())
+
+	tests := []struct {
+		path string
+		want Preview
+	}{
+		{"/og", Preview{
+			Title:       "OG title",
+			Description: "OG description",
+			Image:       srv.URL + "/img/cover.png",
+			SiteName:    "Example",
+		}},
+		{"/fallback", Preview{Title: "Plain", Description: "Twitter description"}},
+		// relative images are resolved against the final location
+		{"/moved", Preview{Title: "Moved", Image: srv.URL + "/articles/cover.png"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := u.Fetch(context.Background(), srv.URL+tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.want.URL = srv.URL + tt.path
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	srv := newServer(t)
+	u := NewWithClient(srv.Client())
+
+	if _, err := u.Fetch(context.Background(), srv.URL+"/json"); !errors.Is(err, ErrNotHTML) {
+		t.Errorf("json: got %v, want ErrNotHTML", err)
+	}
+	for _, path := range []string{"/missing", "/loop"} {
+		if _, err := u.Fetch(context.Background(), srv.URL+path); err == nil {
+			t.Errorf("%s: got no error", path)
+		}
+	}
+	for _, rawURL := range []string{"ftp://example.com/", "javascript:alert(1)", "/relative"} {
+		if _, err := u.Fetch(context.Background(), rawURL); err == nil {
+			t.Errorf("%s: got no error", rawURL)
+		}
+	}
+}
+
+func TestFetchImage(t *testing.T) {
+	srv := newServer(t)
+	u := NewWithClient(srv.Client())
+
+	data, contentType, err := u.FetchImage(context.Background(), srv.URL+"/img/cover.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "image/png" || !bytes.Equal(data, pngHeader) {
+		t.Errorf("got %q, %q", contentType, data)
+	}
+
+	if _, _, err = u.FetchImage(context.Background(), srv.URL+"/img/fake.png"); !errors.Is(err, ErrNotImage) {
+		t.Errorf("svg: got %v, want ErrNotImage", err)
+	}
+	if _, _, err = u.FetchImage(context.Background(), srv.URL+"/img/huge.png"); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("huge: got %v, want ErrImageTooLarge", err)
+	}
+}
+
+func TestNewRejectsLoopback(t *testing.T) {
+	srv := newServer(t)
+	u := New(time.Second)
+
+	if _, err := u.Fetch(context.Background(), srv.URL+"/og"); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("page: got %v, want ErrForbiddenAddress", err)
+	}
+	if _, _, err := u.FetchImage(context.Background(), srv.URL+"/img/cover.png"); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("image: got %v, want ErrForbiddenAddress", err)
+	}
+}
@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// request headers of a delivery
+const (
+	HeaderEvent     = "X-Dumbchat-Event"
+	HeaderDelivery  = "X-Dumbchat-Delivery"
+	HeaderTimestamp = "X-Dumbchat-Timestamp"
+	// "sha256=" followed by the hex HMAC of Timestamp + "." + body
+	HeaderSignature = "X-Dumbchat-Signature"
+)
+
+const userAgent = "dumbchat-webhook/1.0"
+
+var ErrBadSignature = errors.New("webhook signature mismatch")
+
+// Delivery is a single request to a webhook endpoint
+type Delivery struct {
+	ID     int64
+	URL    string
+	Secret string
+	Event  string
+	Body   []byte
+}
+
+// Sender posts deliveries
+type Sender struct {
+	client *http.Client
+}
+
+// New returns a sender with the given timeout per request.
+// Redirects aren't followed, a moved endpoint must be updated by the admin
+func New(timeout time.Duration) *Sender {
+	return NewWithClient(&http.Client{Timeout: timeout})
+}
+
+// NewWithClient uses the given client, e.g. one of httptest.Server
+func NewWithClient(client *http.Client) *Sender {
+	c := *client
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &Sender{client: &c}
+}
+
+// Send posts the delivery and returns the response status.
+// Anything but a 2xx status is an error
+func (s *Sender) Send(ctx context.Context, d Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(HeaderTimestamp, ts)
+	req.Header.Set(HeaderSignature, Sign(d.Secret, ts, d.Body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// let the connection be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the value of the signature header
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a received delivery, for receivers
+// written in Go. Deliveries older than maxAge are rejected to prevent replays
+func Verify(r *http.Request, body []byte, secret string, maxAge time.Duration) error {
+	ts := r.Header.Get(HeaderTimestamp)
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrBadSignature
+	}
+	if age := time.Since(time.Unix(sec, 0)); age > maxAge || age < -maxAge {
+		return fmt.Errorf("webhook timestamp is too old: %w", ErrBadSignature)
+	}
+	got := r.Header.Get(HeaderSignature)
+	if !strings.HasPrefix(got, "sha256=") || !hmac.Equal([]byte(got), []byte(Sign(secret, ts, body))) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// Backoff returns the delay before the given retry (1 for the first one):
+// base doubled on every attempt up to max, with up to 10% jitter
+// so failed deliveries don't retry in lockstep
+func Backoff(retry int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 1; i < retry && d < max; i++ {
+		d *= 2
+	}
+	d = min(d, max)
+	return d + rand.N(d/10+1)
+}
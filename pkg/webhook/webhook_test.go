@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"event":"new_message"}`)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	request := func(ts, signature string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.Header.Set(HeaderTimestamp, ts)
+		r.Header.Set(HeaderSignature, signature)
+		return r
+	}
+
+	if err := Verify(request(ts, Sign("secret", ts, body)), body, "secret", time.Minute); err != nil {
+		t.Errorf("valid signature: %v", err)
+	}
+
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	tests := map[string]struct {
+		r    *http.Request
+		body []byte
+	}{
+		"other secret":    {request(ts, Sign("other", ts, body)), body},
+		"changed body":    {request(ts, Sign("secret", ts, body)), []byte(`{"event":"ban"}`)},
+		"other timestamp": {request(ts, Sign("secret", "1", body)), body},
+		"too old":         {request(old, Sign("secret", old, body)), body},
+		"no timestamp":    {request("", Sign("secret", "", body)), body},
+		"no prefix":       {request(ts, Sign("secret", ts, body)[len("sha256="):]), body},
+	}
+	for name, tt := range tests {
+		if err := Verify(tt.r, tt.body, "secret", time.Minute); !errors.Is(err, ErrBadSignature) {
+			t.Errorf("%s: got %v, want ErrBadSignature", name, err)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base, max := 30*time.Second, time.Hour
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{5, 8 * time.Minute},
+		{8, time.Hour},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		for range 10 {
+			got := Backoff(tt.retry, base, max)
+			if got < tt.want || got > tt.want+tt.want/10 {
+				t.Errorf("Backoff(%d) = %v, want %v plus up to 10%%", tt.retry, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	var got *http.Request
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/fail":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+	s := NewWithClient(srv.Client())
+
+	d := Delivery{ID: 7, URL: srv.URL + "/ok", Secret: "secret", Event: "ban", Body: []byte(`{"event":"ban"}`)}
+	status, err := s.Send(context.Background(), d)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("got %d, %v", status, err)
+	}
+	if got.Header.Get(HeaderEvent) != "ban" || got.Header.Get(HeaderDelivery) != "7" {
+		t.Errorf("unexpected headers %v", got.Header)
+	}
+	if err = Verify(got, gotBody, "secret", time.Minute); err != nil {
+		t.Errorf("receiver can't verify the delivery: %v", err)
+	}
+
+	d.URL = srv.URL + "/fail"
+	if status, err = s.Send(context.Background(), d); err == nil || status != http.StatusServiceUnavailable {
+		t.Errorf("failing endpoint: got %d, %v", status, err)
+	}
+	// redirects aren't followed
+	d.URL = srv.URL + "/moved"
+	if status, err = s.Send(context.Background(), d); err == nil || status != http.StatusFound {
+		t.Errorf("moved endpoint: got %d, %v", status, err)
+	}
+}
@@ -16,3 +16,12 @@ var LoginHTML string
 
 //go:embed templates/layout.html
 var LayoutHTML string
+
+//go:embed templates/admin.html
+var AdminHTML string
+
+//go:embed templates/pinned.html
+var PinnedHTML string
+
+//go:embed templates/search.html
+var SearchHTML string
@@ -2,7 +2,19 @@ function scrollToBottom() {
   const chat = document.getElementById('chat');
   chat.scrollTop = chat.scrollHeight;
 }
-document.addEventListener('DOMContentLoaded', scrollToBottom);
+// permalink pages are centred on the linked message instead
+document.addEventListener('DOMContentLoaded', function () {
+  const focus = document.querySelector('.chat-window[data-focus]');
+  if (!focus) {
+    scrollToBottom();
+    return;
+  }
+  const el = document.querySelector(`#chat .message[data-id="${focus.dataset.focus}"]`);
+  if (el) {
+    el.scrollIntoView({ block: 'center' });
+    el.classList.add('focused');
+  }
+});
 
 document.body.addEventListener('htmx:afterSwap', function (event) {
   if (event.detail.target.id === 'chat') {
@@ -12,6 +24,7 @@ document.body.addEventListener('htmx:afterSwap', function (event) {
 
 textarea = document.querySelector('textarea[name="content"]');
 form = document.querySelector('div.input-area form');
+fileInput = document.querySelector('div.input-area input[name="file"]');
 
 if (textarea && form) {
   textarea.addEventListener('keydown', function(e) {
@@ -21,7 +34,7 @@ if (textarea && form) {
       } else if (!e.shiftKey){
         // then send
         e.preventDefault();
-        if (this.value.trim()) {
+        if (this.value.trim() || (fileInput && fileInput.files.length > 0)) {
           htmx.trigger(form, 'submit');
         }
       }
@@ -30,12 +43,148 @@ if (textarea && form) {
   
   form.addEventListener('htmx:afterRequest', function() {
     textarea.value = '';
+    if (fileInput) fileInput.value = '';
+    clearReply();
     textarea.focus();
   });
 
   textarea.focus();
 }
 
+// replies
+function clearReply() {
+  const input = document.querySelector('div.input-area input[name="reply_to"]');
+  const quote = document.querySelector('div.input-area .reply-quote');
+  if (input) input.value = '';
+  if (quote) quote.hidden = true;
+}
+
+function startReply(btn) {
+  const input = document.querySelector('div.input-area input[name="reply_to"]');
+  const quote = document.querySelector('div.input-area .reply-quote');
+  if (!input || !quote) return;
+
+  input.value = btn.dataset.replyId;
+  quote.querySelector('.sender').textContent = btn.dataset.replyNickname + ':';
+  quote.querySelector('.text').textContent = btn.dataset.replyContent.slice(0, 100);
+  quote.hidden = false;
+  if (textarea) textarea.focus();
+}
+
+function scrollToMessage(id) {
+  const el = document.querySelector(`#chat .message[data-id="${id}"]`);
+  if (!el) return;
+  el.scrollIntoView({ block: 'center', behavior: 'smooth' });
+  el.classList.add('highlight');
+  setTimeout(() => el.classList.remove('highlight'), 2000);
+}
+
+document.body.addEventListener('click', function (e) {
+  const replyBtn = e.target.closest('.chat-window .reply-btn');
+  if (replyBtn) {
+    startReply(replyBtn);
+    return;
+  }
+  if (e.target.closest('.chat-window .reply-cancel')) {
+    clearReply();
+    return;
+  }
+  if (e.target.closest('.chat-window .thread-close')) {
+    document.getElementById('chat-thread').innerHTML = '';
+    return;
+  }
+  // results already on the page are scrolled to, others open their permalink
+  const result = e.target.closest('.chat-window .search-result');
+  if (result) {
+    if (document.querySelector(`#chat .message[data-id="${result.dataset.messageId}"]`)) {
+      e.preventDefault();
+      scrollToMessage(result.dataset.messageId);
+    }
+    return;
+  }
+  const preview = e.target.closest('.chat-window .reply-preview');
+  if (preview && !e.target.closest('.thread-link')) {
+    scrollToMessage(preview.dataset.replyTo);
+  }
+});
+
+// mentions
+// the nickname the visitor posts under, remembered between visits
+// the "#secret" part of a tripcode nickname never leaves the form
+function currentNickname() {
+  return (localStorage.getItem('chat-nickname') || '').split('#')[0].trim();
+}
+
+const nicknameInput = document.querySelector('div.input-area input[name="nickname"]');
+if (nicknameInput) {
+  nicknameInput.value = currentNickname();
+  nicknameInput.addEventListener('change', function() {
+    localStorage.setItem('chat-nickname', this.value.trim());
+    highlightOwnMentions(document);
+  });
+}
+
+function highlightOwnMentions(root) {
+  const nickname = currentNickname().toLowerCase();
+  root.querySelectorAll('.mention[data-mention]').forEach((el) => {
+    el.classList.toggle('mention-me', nickname !== '' && el.dataset.mention.toLowerCase() === nickname);
+  });
+}
+document.addEventListener('DOMContentLoaded', () => highlightOwnMentions(document));
+document.body.addEventListener('htmx:afterSwap', (e) => highlightOwnMentions(e.detail.target));
+document.body.addEventListener('htmx:oobAfterSwap', (e) => highlightOwnMentions(e.detail.target));
+
+let unreadMentions = 0;
+
+function setUnreadMentions(n) {
+  unreadMentions = n;
+  const badge = document.querySelector('.chat-window .mention-badge');
+  if (!badge) return;
+  badge.textContent = '@' + n;
+  badge.hidden = n === 0;
+}
+
+function notifyMention(event) {
+  if (event.nickname.toLowerCase() === currentNickname().toLowerCase()) return;
+  if (document.hidden) {
+    setUnreadMentions(unreadMentions + 1);
+  }
+  if ('Notification' in window && Notification.permission === 'granted') {
+    new Notification(`${event.nickname} mentioned you`, { tag: `mention-${event.id}` });
+  }
+}
+
+document.addEventListener('visibilitychange', () => {
+  if (!document.hidden) setUnreadMentions(0);
+});
+
+document.body.addEventListener('click', function (e) {
+  const badge = e.target.closest('.chat-window .mention-badge');
+  if (!badge) return;
+  setUnreadMentions(0);
+  const mine = document.querySelectorAll('#chat .mention-me');
+  if (mine.length > 0) {
+    scrollToMessage(mine[mine.length - 1].closest('.message').dataset.id);
+  }
+});
+
+// registered nicknames become the one the visitor posts under
+document.body.addEventListener('htmx:afterRequest', function (e) {
+  const claim = e.detail.elt.closest && e.detail.elt.closest('.nickname-claim form');
+  if (!claim || !e.detail.successful || e.detail.requestConfig.verb !== 'post') return;
+  const nickname = claim.querySelector('input[name="nickname"]').value.trim();
+  claim.querySelector('input[name="passphrase"]').value = '';
+  if (nicknameInput) {
+    nicknameInput.value = nickname;
+    nicknameInput.dispatchEvent(new Event('change'));
+  }
+});
+
+// ask once, after the visitor has picked a nickname
+if (nicknameInput && 'Notification' in window && Notification.permission === 'default') {
+  nicknameInput.addEventListener('change', () => Notification.requestPermission(), { once: true });
+}
+
 // track last msg id
 let lastMessageId = 0;
 
@@ -1,10 +1,154 @@
+// a message can be rendered both in the chat and in the thread view
+function removeMessage(id) {
+  document.querySelectorAll(`.message[data-id="${id}"]`).forEach((el) => el.remove());
+}
+
+function isAdminView() {
+  return document.querySelector(".chat-window[data-admin]") !== null;
+}
+
+// admins keep hidden messages, marked as hidden
+function hideMessage(id) {
+  if (!isAdminView()) {
+    removeMessage(id);
+    return;
+  }
+  document.querySelectorAll(`.message[data-id="${id}"]`).forEach((el) => el.classList.add('hidden-message'));
+}
+
+// visitors load the message back into its place, unless it's older
+// than the loaded history; admins drop the hidden mark
+function unhideMessage(id) {
+  const copies = document.querySelectorAll(`.message[data-id="${id}"]`);
+  copies.forEach((el) => el.classList.remove('hidden-message'));
+  if (copies.length > 0) return;
+
+  const chat = document.getElementById('chat');
+  const loaded = [...chat.querySelectorAll(':scope > .message')];
+  if (loaded.length === 0 || Number(loaded[0].dataset.id) > id) return;
+  const next = loaded.find((el) => Number(el.dataset.id) > id);
+  htmx.ajax(
+    "GET",
+    `${window.chatURLs.message}/${id}`,
+    next ? { target: next, swap: "beforebegin" } : { target: "#chat", swap: "beforeend" }
+  );
+}
+
+// swaps rendered HTML from an event in place of the element matching selector
+// in every copy of the message, or inserts it before the reactions
+function swapFragment(id, selector, html) {
+  if (!html) return;
+  document.querySelectorAll(`.message[data-id="${id}"]:not(.editing)`).forEach((el) => {
+    const old = el.querySelector(`:scope > ${selector}`);
+    const anchor = old || el.querySelector(':scope > .reactions');
+    if (!anchor) return;
+    anchor.insertAdjacentHTML(old ? 'afterend' : 'beforebegin', html);
+    const added = old ? old.nextElementSibling : anchor.previousElementSibling;
+    if (old) old.remove();
+    htmx.process(added);
+  });
+}
+
+function applyEdit(data) {
+  swapFragment(data.id, '.message-line', data.line);
+  document.querySelectorAll(`.message[data-id="${data.id}"] .reply-btn`).forEach((btn) => {
+    btn.dataset.replyContent = data.content;
+  });
+}
+
+// rebuilds the reaction buttons from the counts in the event,
+// the visitor's own stay marked
+function applyReactions(id, reactions) {
+  document.querySelectorAll(`.message[data-id="${id}"] .reactions`).forEach((box) => {
+    const picker = box.querySelector('.reaction-picker');
+    if (!picker) return;
+    const mine = new Set([...box.querySelectorAll('.reaction.mine')].map((b) => b.dataset.emoji));
+    box.querySelectorAll('.reaction').forEach((b) => b.remove());
+    for (const r of reactions || []) {
+      const choice = [...picker.querySelectorAll('.reaction-choice')].find((b) => b.dataset.emoji === r.emoji);
+      if (!choice) continue;
+      const btn = choice.cloneNode(false);
+      btn.className = mine.has(r.emoji) ? 'reaction mine' : 'reaction';
+      btn.textContent = `${r.emoji} ${r.count}`;
+      box.insertBefore(btn, picker);
+      htmx.process(btn);
+    }
+  });
+}
+
+// event types sent by the server, anything else is dropped
+const serverEvents = new Set([
+  "hello", "command_reply", "slow_mode", "new_message", "edit_message",
+  "delete_message", "delete_messages", "hide_message", "unhide_message", "reaction_update",
+  "message_preview", "pin", "unpin", "lockdown", "mention",
+]);
+
+// sent with chat form posts, so command replies come back over this connection
+let wsClientId = '';
+
+document.body.addEventListener('htmx:configRequest', function (e) {
+  if (wsClientId && e.detail.elt.closest && e.detail.elt.closest('div.input-area form')) {
+    e.detail.headers['X-Dumbchat-Client'] = wsClientId;
+  }
+});
+
+// appends a line only this visitor sees, dropped on reload
+function showNotice(text) {
+  const chat = document.getElementById('chat');
+  if (!chat || !text) return;
+  const el = document.createElement('div');
+  el.className = 'command-reply';
+  el.textContent = text;
+  chat.appendChild(el);
+  chat.scrollTop = chat.scrollHeight;
+}
+
+function setNickname(nickname) {
+  const input = document.querySelector('div.input-area input[name="nickname"]');
+  if (!input || !nickname) return;
+  input.value = nickname;
+  input.dispatchEvent(new Event('change'));
+}
+
+// replies rendered into the form response when the socket was down
+document.body.addEventListener('htmx:afterSwap', function (e) {
+  if (e.detail.target.id !== 'chat') return;
+  e.detail.target.querySelectorAll('.command-reply[data-nickname]:not([data-applied])').forEach((el) => {
+    el.dataset.applied = '';
+    setNickname(el.dataset.nickname);
+  });
+});
+
 function processWsMessage() {
   try {
-  const conn = new WebSocket(window.location.origin.replace("http", "ws") + window.chatURLs.ws);
+  // the server picks the nickname mentions are delivered to
+  // from the visitor's cookies and the messages they post
+  const url = new URL(window.location.origin.replace("http", "ws") + window.chatURLs.ws);
+  const conn = new WebSocket(url);
+  // the server opens every connection with hello, a later one is ignored
+  let first = true;
   conn.onmessage = (event) => {
       const msg = JSON.parse(event.data);
+      if (!serverEvents.has(msg.type)) return;
 
-      if (msg.type === "new_message") {
+      if (msg.type === "hello" && first) {
+        wsClientId = msg.data.clientId;
+      }
+      first = false;
+
+      if (msg.type === "command_reply") {
+        showNotice(msg.data.text);
+        setNickname(msg.data.nickname);
+      }
+
+      if (msg.type === "slow_mode") {
+        showNotice(msg.data.seconds > 0
+          ? `Slow mode: one message per ${msg.data.seconds} seconds`
+          : 'Slow mode is off');
+      }
+
+      // permalink pages show a slice of history, new messages don't belong there
+      if (msg.type === "new_message" && !document.querySelector(".chat-window[data-focus]")) {
         htmx.ajax(
           "GET",
           `${window.chatURLs.message}/${msg.data.id}`,
@@ -13,8 +157,48 @@ function processWsMessage() {
       }
 
       if (msg.type === "delete_message") {
-        const el = document.querySelector(`[data-id="${msg.data.id}"]`);
-        if (el) el.remove();
+        removeMessage(msg.data.id);
+      }
+
+      if (msg.type === "hide_message") {
+        hideMessage(msg.data.id);
+      }
+
+      if (msg.type === "unhide_message") {
+        unhideMessage(msg.data.id);
+      }
+
+      if (msg.type === "edit_message") {
+        applyEdit(msg.data);
+      }
+
+      if (msg.type === "reaction_update") {
+        applyReactions(msg.data.id, msg.data.reactions);
+      }
+
+      if (msg.type === "message_preview") {
+        swapFragment(msg.data.id, '.link-preview', msg.data.html);
+      }
+
+      if (msg.type === "pin" || msg.type === "unpin") {
+        htmx.ajax("GET", window.chatURLs.pinned, { target: "#chat-pinned", swap: "outerHTML" });
+      }
+
+      if (msg.type === "lockdown") {
+        const banner = document.querySelector(".chat-window .read-only-banner");
+        const input = document.querySelector(".chat-window .input-area");
+        if (banner) banner.hidden = !msg.data.readOnly;
+        if (input) input.hidden = msg.data.readOnly && !isAdminView();
+      }
+
+      if (msg.type === "mention") {
+        notifyMention(msg.data);
+      }
+
+      if (msg.type === "delete_messages") {
+        for (const id of msg.data.ids) {
+          removeMessage(id);
+        }
       }
     };
   } catch(e) {